@@ -6,10 +6,15 @@ import (
 	"github.com/joho/godotenv"
 	"go-finance-tracker/internal/config"
 	"go-finance-tracker/internal/db/psql"
+	"go-finance-tracker/internal/exchange"
+	"go-finance-tracker/internal/recurring"
 	"go-finance-tracker/internal/repository"
 	"go-finance-tracker/internal/rest/handler"
 	"go-finance-tracker/internal/rest/routers"
+	"go-finance-tracker/pkg/email/smtp"
 	"go-finance-tracker/pkg/logger"
+	"go-finance-tracker/pkg/middleware"
+	"go-finance-tracker/pkg/ratelimit"
 	"log"
 	"net/http"
 	"os"
@@ -27,8 +32,12 @@ func main() {
 	logger.InitLogger()
 
 	appConfig = config.App{
-		PORT: os.Getenv("APP_PORT"),
-		DB:   initializeDB(),
+		PORT:      os.Getenv("APP_PORT"),
+		DB:        config.LoadPostgresDB(),
+		SMTP:      config.LoadSMTP(),
+		Auth:      config.LoadAuth(),
+		RateLimit: config.LoadRateLimit(),
+		Rates:     config.LoadExchangeRates(),
 	}
 
 	dbInstance, err := psql.GetDbInstance(appConfig.DB)
@@ -36,47 +45,74 @@ func main() {
 		logger.GetLogger().Fatal("Error initializing DB:", err)
 	}
 
+	emailSender, err := smtp.NewSMTPSender(appConfig.SMTP.From, appConfig.SMTP.Password, appConfig.SMTP.Host, appConfig.SMTP.Port)
+	if err != nil {
+		logger.GetLogger().Fatal("Error initializing email sender:", err)
+	}
+
 	userRepo := repository.NewUserRepository(dbInstance)
 	roleRepo := repository.NewRoleRepository(dbInstance)
+	userTokenRepo := repository.NewUserTokenRepository(dbInstance)
+	sessionRepo := repository.NewSessionRepository(dbInstance)
+	loginAttemptRepo := repository.NewLoginAttemptRepository(dbInstance)
+	mfaRepo := repository.NewMFARepository(dbInstance)
 	financeRepo := repository.NewUserFinanceRepository(dbInstance)
-
-	authHandlers := handler.NewAuthHandler(userRepo, roleRepo)
-	financeHandlers := handler.NewFinanceHandlers(financeRepo)
+	accountRepo := repository.NewAccountRepository(dbInstance)
+	transferRepo := repository.NewTransferRepository(dbInstance)
+	ledgerRepo := repository.NewLedgerRepository(dbInstance)
+	categoryRepo := repository.NewCategoryRepository(dbInstance)
+	reportRepo := repository.NewReportRepository(dbInstance)
+	budgetRepo := repository.NewBudgetRepository(dbInstance)
+	recurringRepo := repository.NewRecurringRepository(dbInstance)
+	exchangeRateRepo := repository.NewExchangeRateRepository(dbInstance)
+	balanceRepo := repository.NewBalanceRepository(dbInstance)
+	auditRepo := repository.NewAuditRepository(dbInstance)
+
+	authHandlers := handler.NewAuthHandler(userRepo, roleRepo, userTokenRepo, sessionRepo, loginAttemptRepo, mfaRepo, emailSender, appConfig.Auth)
+	middleware.SetTokenValidator(authHandlers.ValidateToken)
+	financeHandlers := handler.NewFinanceHandlers(financeRepo, categoryRepo, accountRepo)
+	accountHandlers := handler.NewAccountHandlers(accountRepo)
+	transferHandlers := handler.NewTransferHandlers(transferRepo)
+	ledgerHandlers := handler.NewLedgerHandlers(ledgerRepo)
+	categoryHandlers := handler.NewCategoryHandlers(categoryRepo)
+	reportHandlers := handler.NewReportHandlers(reportRepo)
+	budgetHandlers := handler.NewBudgetHandlers(budgetRepo, categoryRepo)
+	recurringHandlers := handler.NewRecurringHandlers(recurringRepo, categoryRepo, accountRepo)
+	exchangeRateHandlers := handler.NewExchangeRateHandlers(exchangeRateRepo)
+	adminHandlers := handler.NewAdminHandlers(userRepo, roleRepo, auditRepo, balanceRepo, mfaRepo, authHandlers)
+
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(time.Minute))
 
 	r := gin.Default()
 
-	router := routers.NewRouters(authHandlers, financeHandlers)
-	router.SetupRoutes(r)
-	r.Use(rateLimitMiddleware())
+	router := routers.NewRouters(authHandlers, financeHandlers, accountHandlers, transferHandlers, ledgerHandlers, categoryHandlers, reportHandlers, budgetHandlers, recurringHandlers, exchangeRateHandlers, adminHandlers)
+	router.SetupRoutes(r, limiter, appConfig.RateLimit)
 
 	server := &http.Server{
 		Addr:    ":" + appConfig.PORT,
 		Handler: r,
 	}
 
-	gracefulShutdown(server)
-}
-
-func initializeDB() config.PostgresDB {
-	dbConfig := config.PostgresDB{
-		Host:     os.Getenv("POSTGRES_HOST"),
-		Port:     os.Getenv("POSTGRES_PORT"),
-		Sslmode:  os.Getenv("POSTGRES_SSLMODE"),
-		Name:     os.Getenv("POSTGRES_NAME"),
-		User:     os.Getenv("POSTGRES_USER"),
-		Password: os.Getenv("POSTGRES_PASSWORD"),
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	go recurring.NewScheduler(recurringRepo, time.Minute).Run(schedulerCtx)
+	if appConfig.Rates.File != "" {
+		provider := exchange.NewFileProvider(appConfig.Rates.File)
+		go exchange.NewSyncer(provider, exchangeRateRepo, appConfig.Rates.SyncInterval).Run(schedulerCtx)
 	}
 
-	return dbConfig
+	gracefulShutdown(server, stopScheduler)
 }
 
-func gracefulShutdown(server *http.Server) {
+func gracefulShutdown(server *http.Server, onShutdown ...func()) {
 	stop := make(chan os.Signal, 1)
 	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 
 	go func() {
 		<-stop
 		logger.GetLogger().Info("Server is shutting down...")
+		for _, fn := range onShutdown {
+			fn()
+		}
 
 		timeout := 5 * time.Second
 		ctx, cancel := context.WithTimeout(context.Background(), timeout)
@@ -95,17 +131,3 @@ func gracefulShutdown(server *http.Server) {
 		logger.GetLogger().Fatal("Error starting server:", err)
 	}
 }
-
-func rateLimitMiddleware() gin.HandlerFunc {
-	limiter := time.Tick(time.Second)
-
-	return func(c *gin.Context) {
-		select {
-		case <-limiter:
-			c.Next()
-		default:
-			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Rate limit exceeded"})
-			c.Abort()
-		}
-	}
-}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/joho/godotenv"
+	"go-finance-tracker/internal/config"
+	"go-finance-tracker/internal/db/psql"
+	"go-finance-tracker/internal/db/seed"
+	"go-finance-tracker/internal/exchange"
+	"go-finance-tracker/internal/repository"
+	"go-finance-tracker/pkg/logger"
+	"log"
+	"os"
+)
+
+const usage = `usage: maintenance <command> [flags]
+
+commands:
+  recompute-balances [-fix]   rebuild user balances in their base currency and report drift
+  seed [-force]               upsert roles, transaction types and default categories
+  grant-role -user U -role R  give user U the role R, e.g. to create the first ADMIN
+  sync-rates [-file F]        import exchange rates from a CSV file, EXCHANGE_RATES_FILE by default
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Fatalf("Error loading .env file: %s", err)
+	}
+	logger.InitLogger()
+
+	dbInstance, err := psql.GetDbInstance(config.LoadPostgresDB())
+	if err != nil {
+		logger.GetLogger().Fatal("Error initializing DB:", err)
+	}
+
+	switch os.Args[1] {
+	case "recompute-balances":
+		flags := flag.NewFlagSet("recompute-balances", flag.ExitOnError)
+		fix := flags.Bool("fix", false, "overwrite drifted balances with the recomputed ones")
+		_ = flags.Parse(os.Args[2:])
+
+		drifts, err := repository.NewBalanceRepository(dbInstance).Recompute(*fix)
+		if err != nil {
+			logger.GetLogger().Fatal("Balance recomputation failed:", err)
+		}
+
+		for _, drift := range drifts {
+			fmt.Printf("user %d (%s): stored %s, computed %s\n", drift.UserID, drift.Username, drift.Stored, drift.Computed)
+		}
+		if *fix {
+			fmt.Printf("%d balance(s) corrected\n", len(drifts))
+		} else {
+			fmt.Printf("%d balance(s) drifted\n", len(drifts))
+		}
+	case "seed":
+		flags := flag.NewFlagSet("seed", flag.ExitOnError)
+		force := flags.Bool("force", false, "re-apply the seed file even if its version was already applied")
+		_ = flags.Parse(os.Args[2:])
+
+		if err := seed.Run(dbInstance, *force); err != nil {
+			logger.GetLogger().Fatal("Seeding failed:", err)
+		}
+	case "grant-role":
+		flags := flag.NewFlagSet("grant-role", flag.ExitOnError)
+		username := flags.String("user", "", "username to grant the role to")
+		roleName := flags.String("role", "", "name of the role, e.g. ADMIN")
+		_ = flags.Parse(os.Args[2:])
+		if *username == "" || *roleName == "" {
+			flags.Usage()
+			os.Exit(2)
+		}
+
+		user, err := repository.NewUserRepository(dbInstance).GetUserByUsername(*username)
+		if err != nil {
+			logger.GetLogger().Fatal("User lookup failed:", err)
+		}
+		roleRepo := repository.NewRoleRepository(dbInstance)
+		role, err := roleRepo.GetByName(*roleName)
+		if err != nil {
+			logger.GetLogger().Fatal("Role lookup failed:", err)
+		}
+		if err := roleRepo.Assign(user.ID, role, nil); err != nil {
+			logger.GetLogger().Fatal("Granting role failed:", err)
+		}
+		fmt.Printf("granted %s to %s; it applies from their next login or token refresh\n", role.Name, user.Username)
+	case "sync-rates":
+		flags := flag.NewFlagSet("sync-rates", flag.ExitOnError)
+		file := flags.String("file", config.LoadExchangeRates().File, "CSV file of date,from,to,rate rows")
+		_ = flags.Parse(os.Args[2:])
+		if *file == "" {
+			flags.Usage()
+			os.Exit(2)
+		}
+
+		count, err := exchange.Sync(context.Background(), exchange.NewFileProvider(*file),
+			repository.NewExchangeRateRepository(dbInstance))
+		if err != nil {
+			logger.GetLogger().Fatal("Syncing exchange rates failed:", err)
+		}
+		fmt.Printf("%d exchange rate(s) imported\n", count)
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+}
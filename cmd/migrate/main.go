@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/joho/godotenv"
+	"go-finance-tracker/internal/config"
+	"go-finance-tracker/internal/db/migrate"
+	"go-finance-tracker/internal/db/psql"
+	"go-finance-tracker/pkg/logger"
+	"log"
+	"os"
+	"strconv"
+)
+
+const usage = `usage: migrate <command>
+
+commands:
+  up          apply all pending migrations
+  down [N]    roll back the last N migrations (default 1)
+  status      list migrations and whether they are applied
+  to N        migrate up or down to version N (0 rolls back everything)
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Fatalf("Error loading .env file: %s", err)
+	}
+	logger.InitLogger()
+
+	dbInstance, err := psql.Connect(config.LoadPostgresDB())
+	if err != nil {
+		logger.GetLogger().Fatal("Error connecting to DB:", err)
+	}
+	sqlDB, err := dbInstance.DB()
+	if err != nil {
+		logger.GetLogger().Fatal("Error connecting to DB:", err)
+	}
+	defer sqlDB.Close()
+
+	migrator, err := migrate.New(sqlDB)
+	if err != nil {
+		logger.GetLogger().Fatal("Error loading migrations:", err)
+	}
+
+	ctx := context.Background()
+	var done []migrate.Migration
+
+	switch os.Args[1] {
+	case "up":
+		done, err = migrator.Up(ctx)
+	case "down":
+		steps := 1
+		if len(os.Args) > 2 {
+			steps, err = strconv.Atoi(os.Args[2])
+			if err != nil || steps < 1 {
+				log.Fatalf("Invalid number of steps: %s", os.Args[2])
+			}
+		}
+		done, err = migrator.Down(ctx, steps)
+	case "to":
+		if len(os.Args) < 3 {
+			fmt.Fprint(os.Stderr, usage)
+			os.Exit(2)
+		}
+		target, parseErr := strconv.ParseInt(os.Args[2], 10, 64)
+		if parseErr != nil || target < 0 {
+			log.Fatalf("Invalid version: %s", os.Args[2])
+		}
+		done, err = migrator.To(ctx, target)
+	case "status":
+		printStatus(ctx, migrator)
+		return
+	default:
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	for _, migration := range done {
+		fmt.Printf("%04d_%s\n", migration.Version, migration.Name)
+	}
+	if err != nil {
+		logger.GetLogger().Fatal("Migration failed:", err)
+	}
+	fmt.Printf("%d migration(s) run\n", len(done))
+}
+
+func printStatus(ctx context.Context, migrator *migrate.Migrator) {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		logger.GetLogger().Fatal("Error reading migration status:", err)
+	}
+
+	for _, status := range statuses {
+		applied := "pending"
+		if status.Applied() {
+			applied = "applied " + status.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Printf("%04d_%-30s %s\n", status.Version, status.Name, applied)
+	}
+}
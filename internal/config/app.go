@@ -1,6 +1,10 @@
 package config
 
 type App struct {
-	PORT string `env:"APP_PORT" envDefault:"8080"`
-	DB   PostgresDB
+	PORT      string `env:"APP_PORT" envDefault:"8080"`
+	DB        PostgresDB
+	SMTP      SMTP
+	Auth      Auth
+	RateLimit RateLimit
+	Rates     ExchangeRates
 }
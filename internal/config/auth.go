@@ -0,0 +1,137 @@
+package config
+
+import (
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Auth struct {
+	// AppURL is the public address of the web client, used for links that
+	// open a page (e.g. choosing a new password).
+	AppURL string `env:"APP_URL"`
+	// APIURL is the public address of this API, used for links handled by
+	// the API itself (e.g. email verification).
+	APIURL string `env:"API_URL"`
+	// RequireVerifiedEmail refuses logins until the email is verified.
+	RequireVerifiedEmail       bool          `env:"REQUIRE_VERIFIED_EMAIL" envDefault:"false"`
+	VerificationResendCooldown time.Duration `env:"VERIFICATION_RESEND_COOLDOWN" envDefault:"1m"`
+	// AccessTokenTTL is the lifetime of a JWT. Clients renew it through the
+	// refresh endpoint, so it is kept short.
+	AccessTokenTTL time.Duration `env:"ACCESS_TOKEN_TTL" envDefault:"15m"`
+	// RefreshTokenTTL is how long a session stays alive without being used.
+	RefreshTokenTTL time.Duration `env:"REFRESH_TOKEN_TTL" envDefault:"720h"`
+
+	// Cookie attributes for the token cookies. Secure should be enabled
+	// whenever the API is served over HTTPS; it is always on with SameSite
+	// none.
+	CookieSecure   bool          `env:"COOKIE_SECURE" envDefault:"false"`
+	CookieSameSite http.SameSite `env:"COOKIE_SAMESITE" envDefault:"lax"`
+	CookieDomain   string        `env:"COOKIE_DOMAIN"`
+
+	// Login throttling. After each consecutive failure the account waits
+	// LoginBackoffBase doubled per failure before the next attempt, and
+	// LoginMaxFailures failures lock it for LoginLockoutDuration. A client IP
+	// may fail LoginIPMaxFailures times per LoginIPWindow across all accounts.
+	LoginMaxFailures     int           `env:"LOGIN_MAX_FAILURES" envDefault:"5"`
+	LoginLockoutDuration time.Duration `env:"LOGIN_LOCKOUT_DURATION" envDefault:"15m"`
+	LoginBackoffBase     time.Duration `env:"LOGIN_BACKOFF_BASE" envDefault:"1s"`
+	LoginIPMaxFailures   int           `env:"LOGIN_IP_MAX_FAILURES" envDefault:"20"`
+	LoginIPWindow        time.Duration `env:"LOGIN_IP_WINDOW" envDefault:"15m"`
+
+	// Two-factor authentication. MFAIssuer is the name authenticator apps
+	// show, and TOTP secrets are encrypted with MFAEncryptionKey; enrollment
+	// is refused while it is unset. MFATokenTTL is how long a user has to
+	// enter their code after the password was accepted.
+	MFAIssuer        string        `env:"MFA_ISSUER" envDefault:"Go Finance Tracker"`
+	MFAEncryptionKey string        `env:"MFA_ENCRYPTION_KEY"`
+	MFATokenTTL      time.Duration `env:"MFA_TOKEN_TTL" envDefault:"5m"`
+}
+
+func LoadAuth() Auth {
+	requireVerified, _ := strconv.ParseBool(os.Getenv("REQUIRE_VERIFIED_EMAIL"))
+	cookieSecure, _ := strconv.ParseBool(os.Getenv("COOKIE_SECURE"))
+	sameSite := parseSameSite(os.Getenv("COOKIE_SAMESITE"))
+	// Browsers drop SameSite=None cookies that are not Secure.
+	if sameSite == http.SameSiteNoneMode {
+		cookieSecure = true
+	}
+
+	return Auth{
+		AppURL:                     os.Getenv("APP_URL"),
+		APIURL:                     os.Getenv("API_URL"),
+		RequireVerifiedEmail:       requireVerified,
+		VerificationResendCooldown: durationEnv("VERIFICATION_RESEND_COOLDOWN", time.Minute),
+		AccessTokenTTL:             durationEnv("ACCESS_TOKEN_TTL", 15*time.Minute),
+		RefreshTokenTTL:            durationEnv("REFRESH_TOKEN_TTL", 30*24*time.Hour),
+		CookieSecure:               cookieSecure,
+		CookieSameSite:             sameSite,
+		CookieDomain:               os.Getenv("COOKIE_DOMAIN"),
+		LoginMaxFailures:           intEnv("LOGIN_MAX_FAILURES", 5),
+		LoginLockoutDuration:       durationEnv("LOGIN_LOCKOUT_DURATION", 15*time.Minute),
+		LoginBackoffBase:           durationEnv("LOGIN_BACKOFF_BASE", time.Second),
+		LoginIPMaxFailures:         intEnv("LOGIN_IP_MAX_FAILURES", 20),
+		LoginIPWindow:              durationEnv("LOGIN_IP_WINDOW", 15*time.Minute),
+		MFAIssuer:                  stringEnv("MFA_ISSUER", "Go Finance Tracker"),
+		MFAEncryptionKey:           os.Getenv("MFA_ENCRYPTION_KEY"),
+		MFATokenTTL:                durationEnv("MFA_TOKEN_TTL", 5*time.Minute),
+	}
+}
+
+// LoginBackoff returns how long an account with the given number of
+// consecutive failures has to wait before the next attempt.
+func (a Auth) LoginBackoff(failures int) time.Duration {
+	if failures <= 0 {
+		return 0
+	}
+	backoff := a.LoginBackoffBase
+	for i := 1; i < failures && backoff < a.LoginLockoutDuration; i++ {
+		backoff *= 2
+	}
+	if backoff > a.LoginLockoutDuration {
+		backoff = a.LoginLockoutDuration
+	}
+	return backoff
+}
+
+func parseSameSite(value string) http.SameSite {
+	switch strings.ToLower(value) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
+
+// stringEnv returns the environment variable key, falling back when it is
+// unset or empty.
+func stringEnv(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// intEnv parses the environment variable key as a positive int, falling
+// back when it is unset or invalid.
+func intEnv(key string, fallback int) int {
+	n, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || n <= 0 {
+		return fallback
+	}
+	return n
+}
+
+// durationEnv parses the environment variable key as a time.Duration,
+// falling back when it is unset or invalid.
+func durationEnv(key string, fallback time.Duration) time.Duration {
+	d, err := time.ParseDuration(os.Getenv(key))
+	if err != nil || d <= 0 {
+		return fallback
+	}
+	return d
+}
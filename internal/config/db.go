@@ -1,5 +1,7 @@
 package config
 
+import "os"
+
 type PostgresDB struct {
 	Host     string `env:"POSTGRES_HOST"`
 	Port     string `env:"POSTGRES_PORT"`
@@ -8,3 +10,14 @@ type PostgresDB struct {
 	User     string `env:"POSTGRES_USER"`
 	Password string `env:"POSTGRES_PASSWORD"`
 }
+
+func LoadPostgresDB() PostgresDB {
+	return PostgresDB{
+		Host:     os.Getenv("POSTGRES_HOST"),
+		Port:     os.Getenv("POSTGRES_PORT"),
+		Sslmode:  os.Getenv("POSTGRES_SSLMODE"),
+		Name:     os.Getenv("POSTGRES_NAME"),
+		User:     os.Getenv("POSTGRES_USER"),
+		Password: os.Getenv("POSTGRES_PASSWORD"),
+	}
+}
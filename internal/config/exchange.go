@@ -0,0 +1,22 @@
+package config
+
+import (
+	"os"
+	"time"
+)
+
+// ExchangeRates configures where exchange rates come from besides the
+// import endpoint. While File is empty the API does not sync rates itself.
+type ExchangeRates struct {
+	// File is a CSV file of date,from,to,rate rows, re-read every
+	// SyncInterval.
+	File         string        `env:"EXCHANGE_RATES_FILE"`
+	SyncInterval time.Duration `env:"EXCHANGE_RATES_SYNC_INTERVAL" envDefault:"1h"`
+}
+
+func LoadExchangeRates() ExchangeRates {
+	return ExchangeRates{
+		File:         os.Getenv("EXCHANGE_RATES_FILE"),
+		SyncInterval: durationEnv("EXCHANGE_RATES_SYNC_INTERVAL", time.Hour),
+	}
+}
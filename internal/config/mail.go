@@ -0,0 +1,27 @@
+package config
+
+import (
+	"os"
+	"strconv"
+)
+
+type SMTP struct {
+	From     string `env:"SMTP_FROM"`
+	Password string `env:"SMTP_PASSWORD"`
+	Host     string `env:"SMTP_HOST"`
+	Port     int    `env:"SMTP_PORT" envDefault:"587"`
+}
+
+func LoadSMTP() SMTP {
+	port, err := strconv.Atoi(os.Getenv("SMTP_PORT"))
+	if err != nil {
+		port = 587
+	}
+
+	return SMTP{
+		From:     os.Getenv("SMTP_FROM"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		Host:     os.Getenv("SMTP_HOST"),
+		Port:     port,
+	}
+}
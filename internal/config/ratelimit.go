@@ -0,0 +1,39 @@
+package config
+
+import (
+	"go-finance-tracker/pkg/ratelimit"
+	"os"
+	"time"
+)
+
+// RateLimit holds the request limits of each route group, written as
+// "<requests>/<duration>".
+type RateLimit struct {
+	// Auth applies per client IP to the /v1/auth endpoints.
+	Auth ratelimit.Limit `env:"RATE_LIMIT_AUTH" envDefault:"20/1m"`
+	// API applies per user to the finance, category, budget and recurring
+	// endpoints together.
+	API ratelimit.Limit `env:"RATE_LIMIT_API" envDefault:"300/1m"`
+	// Reports applies per user to the report endpoints, which are costlier.
+	Reports ratelimit.Limit `env:"RATE_LIMIT_REPORTS" envDefault:"30/1m"`
+	Admin   ratelimit.Limit `env:"RATE_LIMIT_ADMIN" envDefault:"120/1m"`
+}
+
+func LoadRateLimit() RateLimit {
+	return RateLimit{
+		Auth:    limitEnv("RATE_LIMIT_AUTH", ratelimit.Limit{Requests: 20, Per: time.Minute}),
+		API:     limitEnv("RATE_LIMIT_API", ratelimit.Limit{Requests: 300, Per: time.Minute}),
+		Reports: limitEnv("RATE_LIMIT_REPORTS", ratelimit.Limit{Requests: 30, Per: time.Minute}),
+		Admin:   limitEnv("RATE_LIMIT_ADMIN", ratelimit.Limit{Requests: 120, Per: time.Minute}),
+	}
+}
+
+// limitEnv parses the environment variable key as a ratelimit.Limit,
+// falling back when it is unset or invalid.
+func limitEnv(key string, fallback ratelimit.Limit) ratelimit.Limit {
+	limit, err := ratelimit.ParseLimit(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return limit
+}
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS seed_versions;
+DROP TABLE IF EXISTS finance_records;
+DROP TABLE IF EXISTS categories;
+DROP TABLE IF EXISTS transaction_types;
+DROP TABLE IF EXISTS user_roles;
+DROP TABLE IF EXISTS roles;
+DROP TABLE IF EXISTS users;
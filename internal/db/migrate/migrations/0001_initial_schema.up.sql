@@ -0,0 +1,87 @@
+-- Baseline schema. Every statement is idempotent so that databases created by
+-- the former GORM AutoMigrate step can adopt versioned migrations in place.
+
+CREATE TABLE IF NOT EXISTS users (
+    id          bigserial PRIMARY KEY,
+    created_at  timestamptz,
+    updated_at  timestamptz,
+    deleted_at  timestamptz,
+    name        varchar(35),
+    surname     varchar(35),
+    username    varchar(35) NOT NULL,
+    email       text,
+    password    varchar(255),
+    total_money double precision
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_username ON users (username);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email ON users (email);
+CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users (deleted_at);
+
+CREATE TABLE IF NOT EXISTS roles (
+    id         bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    name       varchar(35)
+);
+CREATE INDEX IF NOT EXISTS idx_roles_deleted_at ON roles (deleted_at);
+
+CREATE TABLE IF NOT EXISTS user_roles (
+    user_id bigint NOT NULL,
+    role_id bigint NOT NULL,
+    PRIMARY KEY (user_id, role_id),
+    CONSTRAINT fk_user_roles_user FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_user_roles_role FOREIGN KEY (role_id) REFERENCES roles (id)
+);
+
+CREATE TABLE IF NOT EXISTS transaction_types (
+    id         bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    name       text
+);
+CREATE INDEX IF NOT EXISTS idx_transaction_types_deleted_at ON transaction_types (deleted_at);
+
+CREATE TABLE IF NOT EXISTS categories (
+    id         bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    name       varchar(64) NOT NULL
+);
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS user_id bigint;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS parent_id bigint;
+CREATE INDEX IF NOT EXISTS idx_categories_deleted_at ON categories (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_categories_user_id ON categories (user_id);
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories (parent_id);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'fk_categories_children') THEN
+        ALTER TABLE categories
+            ADD CONSTRAINT fk_categories_children FOREIGN KEY (parent_id) REFERENCES categories (id);
+    END IF;
+END $$;
+
+CREATE TABLE IF NOT EXISTS finance_records (
+    id                  bigserial PRIMARY KEY,
+    created_at          timestamptz,
+    updated_at          timestamptz,
+    deleted_at          timestamptz,
+    user_id             bigint,
+    amount              double precision,
+    transaction_type_id bigint,
+    category_id         bigint,
+    note                text,
+    CONSTRAINT fk_users_finance_history FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_finance_records_transaction_type FOREIGN KEY (transaction_type_id) REFERENCES transaction_types (id),
+    CONSTRAINT fk_categories_finance_records FOREIGN KEY (category_id) REFERENCES categories (id)
+);
+CREATE INDEX IF NOT EXISTS idx_finance_records_deleted_at ON finance_records (deleted_at);
+CREATE INDEX IF NOT EXISTS idx_finance_records_user_id ON finance_records (user_id);
+
+CREATE TABLE IF NOT EXISTS seed_versions (
+    version    bigint PRIMARY KEY,
+    applied_at timestamptz
+);
@@ -0,0 +1,2 @@
+ALTER TABLE finance_records ALTER COLUMN amount TYPE double precision USING amount::double precision;
+ALTER TABLE users ALTER COLUMN total_money TYPE double precision USING total_money::double precision;
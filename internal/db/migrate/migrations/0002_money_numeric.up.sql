@@ -0,0 +1,24 @@
+-- Amounts are exact decimals (money.Amount). Refuse to convert if any stored
+-- float has more than two fractional digits instead of silently rounding it.
+DO $$
+DECLARE
+    lossy bigint;
+BEGIN
+    IF (SELECT data_type FROM information_schema.columns
+        WHERE table_schema = current_schema() AND table_name = 'finance_records' AND column_name = 'amount') = 'double precision' THEN
+        SELECT count(*) INTO lossy FROM finance_records WHERE amount::numeric <> round(amount::numeric, 2);
+        IF lossy > 0 THEN
+            RAISE EXCEPTION 'finance_records.amount: % value(s) have more than 2 fractional digits', lossy;
+        END IF;
+        ALTER TABLE finance_records ALTER COLUMN amount TYPE numeric(19,2) USING round(amount::numeric, 2);
+    END IF;
+
+    IF (SELECT data_type FROM information_schema.columns
+        WHERE table_schema = current_schema() AND table_name = 'users' AND column_name = 'total_money') = 'double precision' THEN
+        SELECT count(*) INTO lossy FROM users WHERE total_money::numeric <> round(total_money::numeric, 2);
+        IF lossy > 0 THEN
+            RAISE EXCEPTION 'users.total_money: % value(s) have more than 2 fractional digits', lossy;
+        END IF;
+        ALTER TABLE users ALTER COLUMN total_money TYPE numeric(19,2) USING round(total_money::numeric, 2);
+    END IF;
+END $$;
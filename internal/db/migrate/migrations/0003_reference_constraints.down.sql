@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS idx_transaction_types_name;
+DROP INDEX IF EXISTS idx_roles_name;
+
+ALTER TABLE finance_records ALTER COLUMN transaction_type_id DROP NOT NULL;
+ALTER TABLE finance_records ALTER COLUMN amount DROP NOT NULL;
+ALTER TABLE finance_records ALTER COLUMN user_id DROP NOT NULL;
+
+ALTER TABLE users ALTER COLUMN total_money DROP NOT NULL;
+ALTER TABLE users ALTER COLUMN total_money DROP DEFAULT;
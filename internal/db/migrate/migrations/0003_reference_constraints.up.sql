@@ -0,0 +1,10 @@
+UPDATE users SET total_money = 0 WHERE total_money IS NULL;
+ALTER TABLE users ALTER COLUMN total_money SET DEFAULT 0;
+ALTER TABLE users ALTER COLUMN total_money SET NOT NULL;
+
+ALTER TABLE finance_records ALTER COLUMN user_id SET NOT NULL;
+ALTER TABLE finance_records ALTER COLUMN amount SET NOT NULL;
+ALTER TABLE finance_records ALTER COLUMN transaction_type_id SET NOT NULL;
+
+CREATE UNIQUE INDEX idx_roles_name ON roles (name) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_transaction_types_name ON transaction_types (name) WHERE deleted_at IS NULL;
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_finance_records_user_created_at;
@@ -0,0 +1 @@
+CREATE INDEX IF NOT EXISTS idx_finance_records_user_created_at ON finance_records (user_id, created_at) WHERE deleted_at IS NULL;
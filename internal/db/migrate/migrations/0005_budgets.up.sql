@@ -0,0 +1,18 @@
+CREATE TABLE budgets (
+    id          bigserial PRIMARY KEY,
+    created_at  timestamptz,
+    updated_at  timestamptz,
+    deleted_at  timestamptz,
+    user_id     bigint         NOT NULL,
+    category_id bigint         NOT NULL,
+    period_type varchar(16)    NOT NULL,
+    amount      numeric(19,2)  NOT NULL,
+    start_date  date           NOT NULL,
+    rollover    boolean        NOT NULL DEFAULT false,
+    CONSTRAINT fk_budgets_user FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_budgets_category FOREIGN KEY (category_id) REFERENCES categories (id),
+    CONSTRAINT chk_budgets_period_type CHECK (period_type IN ('WEEKLY', 'MONTHLY', 'YEARLY')),
+    CONSTRAINT chk_budgets_amount CHECK (amount > 0)
+);
+CREATE INDEX idx_budgets_deleted_at ON budgets (deleted_at);
+CREATE INDEX idx_budgets_user_id ON budgets (user_id);
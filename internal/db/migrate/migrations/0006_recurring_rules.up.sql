@@ -0,0 +1,34 @@
+CREATE TABLE recurring_rules (
+    id                  bigserial PRIMARY KEY,
+    created_at          timestamptz,
+    updated_at          timestamptz,
+    deleted_at          timestamptz,
+    user_id             bigint        NOT NULL,
+    amount              numeric(19,2) NOT NULL,
+    transaction_type_id bigint        NOT NULL,
+    category_id         bigint,
+    note                text,
+    frequency           varchar(16)   NOT NULL,
+    "interval"          integer       NOT NULL DEFAULT 1,
+    day_of_month        integer       NOT NULL DEFAULT 0,
+    timezone            varchar(64)   NOT NULL,
+    start_date          timestamptz   NOT NULL,
+    end_date            timestamptz,
+    count               integer,
+    next_occurrence     integer       NOT NULL DEFAULT 0,
+    next_run_at         timestamptz,
+    CONSTRAINT fk_recurring_rules_user FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_recurring_rules_transaction_type FOREIGN KEY (transaction_type_id) REFERENCES transaction_types (id),
+    CONSTRAINT fk_recurring_rules_category FOREIGN KEY (category_id) REFERENCES categories (id),
+    CONSTRAINT chk_recurring_rules_frequency CHECK (frequency IN ('DAILY', 'WEEKLY', 'MONTHLY', 'YEARLY')),
+    CONSTRAINT chk_recurring_rules_interval CHECK ("interval" >= 1),
+    CONSTRAINT chk_recurring_rules_day_of_month CHECK (day_of_month BETWEEN 0 AND 31)
+);
+CREATE INDEX idx_recurring_rules_deleted_at ON recurring_rules (deleted_at);
+CREATE INDEX idx_recurring_rules_user_id ON recurring_rules (user_id);
+CREATE INDEX idx_recurring_rules_next_run_at ON recurring_rules (next_run_at) WHERE deleted_at IS NULL;
+
+ALTER TABLE finance_records ADD COLUMN recurring_rule_id bigint REFERENCES recurring_rules (id);
+ALTER TABLE finance_records ADD COLUMN occurrence integer;
+CREATE UNIQUE INDEX idx_finance_records_recurring_occurrence
+    ON finance_records (recurring_rule_id, occurrence) WHERE recurring_rule_id IS NOT NULL;
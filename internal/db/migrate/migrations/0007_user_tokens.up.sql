@@ -0,0 +1,17 @@
+CREATE TABLE user_tokens (
+    id         bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    user_id    bigint      NOT NULL,
+    purpose    varchar(32) NOT NULL,
+    token_hash varchar(64) NOT NULL,
+    expires_at timestamptz NOT NULL,
+    used_at    timestamptz,
+    CONSTRAINT fk_user_tokens_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX idx_user_tokens_deleted_at ON user_tokens (deleted_at);
+CREATE INDEX idx_user_tokens_user_id ON user_tokens (user_id);
+CREATE UNIQUE INDEX idx_user_tokens_token_hash ON user_tokens (token_hash);
+
+ALTER TABLE users ADD COLUMN password_changed_at timestamptz;
@@ -0,0 +1,4 @@
+ALTER TABLE users ADD COLUMN email_verified_at timestamptz;
+
+-- Accounts created before verification existed keep working.
+UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL;
@@ -0,0 +1,30 @@
+CREATE TABLE sessions (
+    id           bigserial PRIMARY KEY,
+    created_at   timestamptz,
+    updated_at   timestamptz,
+    deleted_at   timestamptz,
+    user_id      bigint       NOT NULL,
+    device       varchar(64),
+    ip           varchar(45),
+    user_agent   varchar(255),
+    last_used_at timestamptz  NOT NULL,
+    expires_at   timestamptz  NOT NULL,
+    revoked_at   timestamptz,
+    CONSTRAINT fk_sessions_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX idx_sessions_deleted_at ON sessions (deleted_at);
+CREATE INDEX idx_sessions_user_id ON sessions (user_id);
+
+CREATE TABLE refresh_tokens (
+    id         bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    session_id bigint      NOT NULL,
+    token_hash varchar(64) NOT NULL,
+    used_at    timestamptz,
+    CONSTRAINT fk_refresh_tokens_session FOREIGN KEY (session_id) REFERENCES sessions (id)
+);
+CREATE INDEX idx_refresh_tokens_deleted_at ON refresh_tokens (deleted_at);
+CREATE INDEX idx_refresh_tokens_session_id ON refresh_tokens (session_id);
+CREATE UNIQUE INDEX idx_refresh_tokens_token_hash ON refresh_tokens (token_hash);
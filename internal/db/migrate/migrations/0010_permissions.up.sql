@@ -0,0 +1,17 @@
+CREATE TABLE permissions (
+    id         bigserial PRIMARY KEY,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    name       varchar(64) NOT NULL
+);
+CREATE INDEX idx_permissions_deleted_at ON permissions (deleted_at);
+CREATE UNIQUE INDEX idx_permissions_name ON permissions (name) WHERE deleted_at IS NULL;
+
+CREATE TABLE role_permissions (
+    role_id       bigint NOT NULL,
+    permission_id bigint NOT NULL,
+    PRIMARY KEY (role_id, permission_id),
+    CONSTRAINT fk_role_permissions_role FOREIGN KEY (role_id) REFERENCES roles (id),
+    CONSTRAINT fk_role_permissions_permission FOREIGN KEY (permission_id) REFERENCES permissions (id)
+);
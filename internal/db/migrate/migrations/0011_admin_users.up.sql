@@ -0,0 +1,15 @@
+ALTER TABLE users ADD COLUMN locked_at timestamptz;
+
+CREATE TABLE audit_logs (
+    id             bigserial PRIMARY KEY,
+    created_at     timestamptz NOT NULL DEFAULT now(),
+    actor_id       bigint      NOT NULL,
+    action         varchar(32) NOT NULL,
+    target_user_id bigint      NOT NULL,
+    ip             varchar(45),
+    details        jsonb,
+    CONSTRAINT fk_audit_logs_actor FOREIGN KEY (actor_id) REFERENCES users (id),
+    CONSTRAINT fk_audit_logs_target_user FOREIGN KEY (target_user_id) REFERENCES users (id)
+);
+CREATE INDEX idx_audit_logs_actor_id ON audit_logs (actor_id);
+CREATE INDEX idx_audit_logs_target_user_id ON audit_logs (target_user_id);
@@ -0,0 +1,15 @@
+ALTER TABLE users ADD COLUMN failed_logins integer NOT NULL DEFAULT 0;
+ALTER TABLE users ADD COLUMN last_failed_login_at timestamptz;
+ALTER TABLE users ADD COLUMN locked_until timestamptz;
+
+CREATE TABLE login_attempts (
+    id         bigserial PRIMARY KEY,
+    created_at timestamptz NOT NULL,
+    username   varchar(35) NOT NULL,
+    user_id    bigint,
+    ip         varchar(45) NOT NULL,
+    succeeded  boolean     NOT NULL,
+    CONSTRAINT fk_login_attempts_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX idx_login_attempts_user_id ON login_attempts (user_id);
+CREATE INDEX idx_login_attempts_ip_created_at ON login_attempts (ip, created_at);
@@ -0,0 +1,13 @@
+ALTER TABLE users ADD COLUMN totp_secret varchar(255);
+ALTER TABLE users ADD COLUMN totp_enabled_at timestamptz;
+ALTER TABLE users ADD COLUMN totp_last_step bigint NOT NULL DEFAULT 0;
+
+CREATE TABLE recovery_codes (
+    id         bigserial PRIMARY KEY,
+    created_at timestamptz NOT NULL,
+    user_id    bigint      NOT NULL,
+    code_hash  varchar(64) NOT NULL,
+    used_at    timestamptz,
+    CONSTRAINT fk_recovery_codes_user FOREIGN KEY (user_id) REFERENCES users (id)
+);
+CREATE INDEX idx_recovery_codes_user_id ON recovery_codes (user_id);
@@ -0,0 +1,3 @@
+ALTER TABLE recurring_rules DROP COLUMN IF EXISTS account_id;
+ALTER TABLE finance_records DROP COLUMN IF EXISTS account_id;
+DROP TABLE IF EXISTS accounts;
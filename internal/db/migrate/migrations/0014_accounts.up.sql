@@ -0,0 +1,33 @@
+CREATE TABLE accounts (
+    id              bigserial PRIMARY KEY,
+    created_at      timestamptz,
+    updated_at      timestamptz,
+    deleted_at      timestamptz,
+    user_id         bigint        NOT NULL,
+    name            varchar(64)   NOT NULL,
+    type            varchar(16)   NOT NULL,
+    currency        varchar(3)    NOT NULL,
+    opening_balance numeric(19,2) NOT NULL DEFAULT 0,
+    archived        boolean       NOT NULL DEFAULT false,
+    CONSTRAINT fk_accounts_user FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT chk_accounts_type CHECK (type IN ('CHECKING', 'SAVINGS', 'CREDIT_CARD', 'CASH', 'OTHER'))
+);
+CREATE INDEX idx_accounts_deleted_at ON accounts (deleted_at);
+CREATE INDEX idx_accounts_user_id ON accounts (user_id);
+
+-- Every existing user gets a default account holding their whole history.
+INSERT INTO accounts (created_at, updated_at, user_id, name, type, currency)
+SELECT now(), now(), id, 'Main', 'CHECKING', 'USD' FROM users;
+
+ALTER TABLE finance_records ADD COLUMN account_id bigint;
+UPDATE finance_records fr SET account_id = a.id FROM accounts a WHERE a.user_id = fr.user_id;
+ALTER TABLE finance_records ALTER COLUMN account_id SET NOT NULL;
+ALTER TABLE finance_records
+    ADD CONSTRAINT fk_finance_records_account FOREIGN KEY (account_id) REFERENCES accounts (id);
+CREATE INDEX idx_finance_records_account_id ON finance_records (account_id);
+
+ALTER TABLE recurring_rules ADD COLUMN account_id bigint;
+UPDATE recurring_rules rr SET account_id = a.id FROM accounts a WHERE a.user_id = rr.user_id;
+ALTER TABLE recurring_rules ALTER COLUMN account_id SET NOT NULL;
+ALTER TABLE recurring_rules
+    ADD CONSTRAINT fk_recurring_rules_account FOREIGN KEY (account_id) REFERENCES accounts (id);
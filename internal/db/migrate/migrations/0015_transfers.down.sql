@@ -0,0 +1 @@
+ALTER TABLE finance_records DROP COLUMN IF EXISTS transfer_record_id;
@@ -0,0 +1,4 @@
+ALTER TABLE finance_records ADD COLUMN transfer_record_id bigint;
+ALTER TABLE finance_records
+    ADD CONSTRAINT fk_finance_records_transfer_record FOREIGN KEY (transfer_record_id) REFERENCES finance_records (id);
+CREATE INDEX idx_finance_records_transfer_record_id ON finance_records (transfer_record_id);
@@ -0,0 +1,18 @@
+ALTER TABLE users ADD COLUMN base_currency varchar(3) NOT NULL DEFAULT 'USD';
+
+ALTER TABLE finance_records ADD COLUMN currency varchar(3);
+UPDATE finance_records fr SET currency = a.currency FROM accounts a WHERE a.id = fr.account_id;
+ALTER TABLE finance_records ALTER COLUMN currency SET NOT NULL;
+
+CREATE TABLE exchange_rates (
+    id            bigserial PRIMARY KEY,
+    created_at    timestamptz,
+    updated_at    timestamptz,
+    date          date           NOT NULL,
+    from_currency varchar(3)     NOT NULL,
+    to_currency   varchar(3)     NOT NULL,
+    rate          numeric(24,10) NOT NULL,
+    CONSTRAINT chk_exchange_rates_rate CHECK (rate > 0)
+);
+-- Reports look up the latest rate on or before a date for a pair.
+CREATE UNIQUE INDEX idx_exchange_rates_pair_date ON exchange_rates (from_currency, to_currency, date);
@@ -0,0 +1,49 @@
+-- Finance records become a table again, filled from the journal and from
+-- the archived records.
+CREATE TEMPORARY TABLE finance_records_journal AS SELECT * FROM finance_records;
+DROP VIEW IF EXISTS finance_records;
+
+CREATE TABLE finance_records (
+    id                  bigserial PRIMARY KEY,
+    created_at          timestamptz,
+    updated_at          timestamptz,
+    deleted_at          timestamptz,
+    user_id             bigint        NOT NULL,
+    amount              numeric(19,2) NOT NULL,
+    transaction_type_id bigint        NOT NULL,
+    category_id         bigint,
+    note                text,
+    recurring_rule_id   bigint REFERENCES recurring_rules (id),
+    occurrence          integer,
+    account_id          bigint        NOT NULL,
+    transfer_record_id  bigint,
+    currency            varchar(3)    NOT NULL,
+    CONSTRAINT fk_users_finance_history FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_finance_records_transaction_type FOREIGN KEY (transaction_type_id) REFERENCES transaction_types (id),
+    CONSTRAINT fk_categories_finance_records FOREIGN KEY (category_id) REFERENCES categories (id),
+    CONSTRAINT fk_finance_records_account FOREIGN KEY (account_id) REFERENCES accounts (id),
+    CONSTRAINT fk_finance_records_transfer_record FOREIGN KEY (transfer_record_id) REFERENCES finance_records (id)
+);
+INSERT INTO finance_records (id, created_at, updated_at, deleted_at, user_id, amount, transaction_type_id,
+    category_id, note, recurring_rule_id, occurrence, account_id, transfer_record_id, currency)
+SELECT id, created_at, updated_at, NULL, user_id, amount, transaction_type_id, category_id, note,
+    recurring_rule_id, occurrence, account_id, transfer_record_id, currency
+FROM finance_records_journal
+UNION ALL
+SELECT id, created_at, updated_at, deleted_at, user_id, amount, transaction_type_id, category_id, note,
+    recurring_rule_id, occurrence, account_id, transfer_record_id, currency
+FROM finance_records_archive;
+SELECT setval(pg_get_serial_sequence('finance_records', 'id'), GREATEST((SELECT MAX(id) FROM finance_records), 1));
+CREATE INDEX idx_finance_records_deleted_at ON finance_records (deleted_at);
+CREATE INDEX idx_finance_records_user_id ON finance_records (user_id);
+CREATE INDEX idx_finance_records_user_created_at ON finance_records (user_id, created_at) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX idx_finance_records_recurring_occurrence
+    ON finance_records (recurring_rule_id, occurrence) WHERE recurring_rule_id IS NOT NULL;
+CREATE INDEX idx_finance_records_account_id ON finance_records (account_id);
+CREATE INDEX idx_finance_records_transfer_record_id ON finance_records (transfer_record_id);
+
+DROP TABLE finance_records_journal;
+DROP TABLE IF EXISTS finance_records_archive;
+DROP TABLE IF EXISTS postings;
+DROP TABLE IF EXISTS journal_entries;
+DROP TABLE IF EXISTS ledger_accounts;
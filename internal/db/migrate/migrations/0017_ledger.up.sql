@@ -0,0 +1,193 @@
+-- The journal becomes the store of income, expenses and transfers:
+-- finance_records turns into a view over the postings to accounts, so the
+-- finance API can no longer disagree with the ledger. Every record keeps its
+-- ID as the ID of the posting to its account.
+--
+-- Soft-deleted records and records of zero cannot be posted. They are not
+-- lost: they are moved to finance_records_archive as they were, and the down
+-- migration puts them back.
+
+CREATE TABLE ledger_accounts (
+    id          bigserial PRIMARY KEY,
+    created_at  timestamptz,
+    updated_at  timestamptz,
+    user_id     bigint       NOT NULL,
+    name        varchar(128) NOT NULL,
+    type        varchar(16)  NOT NULL,
+    account_id  bigint,
+    category_id bigint,
+    CONSTRAINT fk_ledger_accounts_user FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_ledger_accounts_account FOREIGN KEY (account_id) REFERENCES accounts (id),
+    CONSTRAINT fk_ledger_accounts_category FOREIGN KEY (category_id) REFERENCES categories (id),
+    CONSTRAINT chk_ledger_accounts_type CHECK (type IN ('ASSET', 'LIABILITY', 'INCOME', 'EXPENSE', 'EQUITY')),
+    CONSTRAINT chk_ledger_accounts_link CHECK (account_id IS NULL OR category_id IS NULL)
+);
+CREATE INDEX idx_ledger_accounts_user_id ON ledger_accounts (user_id);
+CREATE UNIQUE INDEX idx_ledger_accounts_account_id ON ledger_accounts (account_id);
+CREATE UNIQUE INDEX idx_ledger_accounts_category ON ledger_accounts (user_id, category_id, type);
+
+CREATE TABLE journal_entries (
+    id                 bigserial PRIMARY KEY,
+    created_at         timestamptz,
+    updated_at         timestamptz,
+    user_id            bigint      NOT NULL,
+    date               timestamptz NOT NULL,
+    description        text,
+    opening_account_id bigint,
+    recurring_rule_id  bigint,
+    occurrence         integer,
+    CONSTRAINT fk_journal_entries_user FOREIGN KEY (user_id) REFERENCES users (id),
+    CONSTRAINT fk_journal_entries_opening_account FOREIGN KEY (opening_account_id) REFERENCES accounts (id),
+    CONSTRAINT fk_journal_entries_recurring_rule FOREIGN KEY (recurring_rule_id) REFERENCES recurring_rules (id)
+);
+CREATE INDEX idx_journal_entries_user_id_date ON journal_entries (user_id, date);
+CREATE UNIQUE INDEX idx_journal_entries_opening_account_id ON journal_entries (opening_account_id);
+CREATE UNIQUE INDEX idx_journal_entries_recurring_occurrence
+    ON journal_entries (recurring_rule_id, occurrence) WHERE recurring_rule_id IS NOT NULL;
+
+-- Postings are debits when positive and credits when negative; the
+-- repository only writes entries whose postings sum to zero per currency.
+CREATE TABLE postings (
+    id                bigserial PRIMARY KEY,
+    journal_entry_id  bigint        NOT NULL,
+    ledger_account_id bigint        NOT NULL,
+    amount            numeric(19,2) NOT NULL,
+    currency          varchar(3)    NOT NULL,
+    CONSTRAINT fk_postings_journal_entry FOREIGN KEY (journal_entry_id) REFERENCES journal_entries (id) ON DELETE CASCADE,
+    CONSTRAINT fk_postings_ledger_account FOREIGN KEY (ledger_account_id) REFERENCES ledger_accounts (id),
+    CONSTRAINT chk_postings_amount CHECK (amount <> 0)
+);
+CREATE INDEX idx_postings_journal_entry_id ON postings (journal_entry_id);
+CREATE INDEX idx_postings_ledger_account_id ON postings (ledger_account_id);
+
+-- Ledger accounts for every account, every category and type in use, and
+-- the equity account opening balances are booked against.
+INSERT INTO ledger_accounts (created_at, updated_at, user_id, name, type, account_id)
+SELECT now(), now(), user_id, name, CASE type WHEN 'CREDIT_CARD' THEN 'LIABILITY' ELSE 'ASSET' END, id
+FROM accounts
+WHERE deleted_at IS NULL;
+
+INSERT INTO ledger_accounts (created_at, updated_at, user_id, name, type, category_id)
+SELECT DISTINCT now(), now(), fr.user_id, c.name, tt.name, fr.category_id
+FROM finance_records fr
+JOIN transaction_types tt ON tt.id = fr.transaction_type_id
+JOIN categories c ON c.id = fr.category_id
+WHERE fr.deleted_at IS NULL AND fr.amount <> 0 AND tt.name IN ('INCOME', 'EXPENSE');
+
+INSERT INTO ledger_accounts (created_at, updated_at, user_id, name, type)
+SELECT DISTINCT now(), now(), user_id, 'Opening Balances', 'EQUITY'
+FROM accounts
+WHERE deleted_at IS NULL AND opening_balance <> 0;
+
+-- Links each entry to the record it is built from until the records are
+-- dropped.
+ALTER TABLE journal_entries ADD COLUMN finance_record_id bigint;
+
+-- One entry per income or expense and one per transfer, found through the
+-- half leaving the source account.
+INSERT INTO journal_entries (created_at, updated_at, user_id, date, description, finance_record_id,
+    recurring_rule_id, occurrence)
+SELECT COALESCE(fr.created_at, now()), COALESCE(fr.updated_at, fr.created_at, now()), fr.user_id,
+    fr.created_at, fr.note, fr.id, fr.recurring_rule_id, fr.occurrence
+FROM finance_records fr
+JOIN transaction_types tt ON tt.id = fr.transaction_type_id
+WHERE fr.deleted_at IS NULL AND fr.amount <> 0
+    AND (tt.name IN ('INCOME', 'EXPENSE')
+        OR (tt.name = 'TRANSFER' AND fr.amount < 0 AND fr.transfer_record_id IS NOT NULL));
+
+INSERT INTO postings (id, journal_entry_id, ledger_account_id, amount, currency)
+SELECT fr.id, je.id, la.id, CASE tt.name WHEN 'EXPENSE' THEN -fr.amount ELSE fr.amount END, fr.currency
+FROM finance_records fr
+JOIN transaction_types tt ON tt.id = fr.transaction_type_id
+JOIN journal_entries je ON je.finance_record_id =
+    CASE WHEN tt.name = 'TRANSFER' AND fr.amount > 0 THEN fr.transfer_record_id ELSE fr.id END
+JOIN ledger_accounts la ON la.account_id = fr.account_id
+WHERE fr.deleted_at IS NULL;
+
+SELECT setval(pg_get_serial_sequence('postings', 'id'), GREATEST((SELECT MAX(id) FROM finance_records), 1));
+
+INSERT INTO postings (journal_entry_id, ledger_account_id, amount, currency)
+SELECT je.id, la.id, CASE tt.name WHEN 'EXPENSE' THEN fr.amount ELSE -fr.amount END, fr.currency
+FROM journal_entries je
+JOIN finance_records fr ON fr.id = je.finance_record_id
+JOIN transaction_types tt ON tt.id = fr.transaction_type_id
+JOIN ledger_accounts la ON la.user_id = fr.user_id AND la.category_id = fr.category_id AND la.type = tt.name
+WHERE tt.name IN ('INCOME', 'EXPENSE');
+
+INSERT INTO journal_entries (created_at, updated_at, user_id, date, description, opening_account_id)
+SELECT now(), now(), user_id, COALESCE(created_at, now()), 'Opening Balances', id
+FROM accounts
+WHERE deleted_at IS NULL AND opening_balance <> 0;
+
+INSERT INTO postings (journal_entry_id, ledger_account_id, amount, currency)
+SELECT je.id, la.id, a.opening_balance, a.currency
+FROM journal_entries je
+JOIN accounts a ON a.id = je.opening_account_id
+JOIN ledger_accounts la ON la.account_id = a.id;
+
+INSERT INTO postings (journal_entry_id, ledger_account_id, amount, currency)
+SELECT je.id, la.id, -a.opening_balance, a.currency
+FROM journal_entries je
+JOIN accounts a ON a.id = je.opening_account_id
+JOIN ledger_accounts la ON la.user_id = a.user_id AND la.name = 'Opening Balances' AND la.type = 'EQUITY'
+    AND la.account_id IS NULL AND la.category_id IS NULL;
+
+-- Every record that did not become a posting is archived before the table
+-- goes away.
+CREATE TABLE finance_records_archive AS
+SELECT fr.*
+FROM finance_records fr
+WHERE NOT EXISTS (SELECT 1 FROM postings p WHERE p.id = fr.id);
+ALTER TABLE finance_records_archive ADD PRIMARY KEY (id);
+
+ALTER TABLE journal_entries DROP COLUMN finance_record_id;
+DROP TABLE finance_records;
+
+-- A finance record is a posting to an account outside of opening balances.
+-- Its type and category come from the first posting to a category; entries
+-- without one are transfers, whose halves point at each other.
+CREATE VIEW finance_records AS
+SELECT p.id,
+    je.date AS created_at,
+    COALESCE(je.updated_at, je.created_at) AS updated_at,
+    NULL::timestamptz AS deleted_at,
+    je.user_id,
+    la.account_id,
+    CASE WHEN line.type = 'EXPENSE' THEN -p.amount ELSE p.amount END AS amount,
+    p.currency,
+    (
+        SELECT tt.id FROM transaction_types tt
+        WHERE tt.deleted_at IS NULL AND tt.name = COALESCE(line.type, 'TRANSFER')
+        ORDER BY tt.id
+        LIMIT 1
+    ) AS transaction_type_id,
+    COALESCE(line.category_id, (
+        SELECT c.id FROM categories c
+        WHERE c.user_id IS NULL AND c.parent_id IS NULL AND c.name = 'Transfers' AND c.deleted_at IS NULL
+        ORDER BY c.id
+        LIMIT 1
+    )) AS category_id,
+    COALESCE(je.description, '') AS note,
+    je.recurring_rule_id,
+    je.occurrence,
+    other.id AS transfer_record_id,
+    je.id AS journal_entry_id
+FROM postings p
+JOIN journal_entries je ON je.id = p.journal_entry_id
+JOIN ledger_accounts la ON la.id = p.ledger_account_id AND la.account_id IS NOT NULL
+LEFT JOIN LATERAL (
+    SELECT cla.category_id, cla.type
+    FROM postings cp
+    JOIN ledger_accounts cla ON cla.id = cp.ledger_account_id
+    WHERE cp.journal_entry_id = je.id AND cla.category_id IS NOT NULL
+    ORDER BY cp.id
+    LIMIT 1
+) line ON true
+LEFT JOIN LATERAL (
+    SELECT op.id
+    FROM postings op
+    JOIN ledger_accounts ola ON ola.id = op.ledger_account_id
+    WHERE op.journal_entry_id = je.id AND op.id <> p.id AND ola.account_id IS NOT NULL
+    LIMIT 1
+) other ON true
+WHERE je.opening_account_id IS NULL;
@@ -0,0 +1,2 @@
+DROP VIEW IF EXISTS finance_splits;
+ALTER TABLE postings DROP COLUMN IF EXISTS note;
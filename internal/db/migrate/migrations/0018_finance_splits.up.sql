@@ -0,0 +1,21 @@
+-- A split record is one journal entry posting to several categories; each
+-- line is one of those postings and keeps its own note.
+ALTER TABLE postings ADD COLUMN note text;
+
+-- The lines of a record posting to more than one category.
+CREATE VIEW finance_splits AS
+SELECT cp.id,
+    ap.id AS finance_record_id,
+    cla.category_id,
+    CASE WHEN cla.type = 'EXPENSE' THEN cp.amount ELSE -cp.amount END AS amount,
+    COALESCE(cp.note, '') AS note
+FROM postings cp
+JOIN ledger_accounts cla ON cla.id = cp.ledger_account_id AND cla.category_id IS NOT NULL
+JOIN postings ap ON ap.journal_entry_id = cp.journal_entry_id
+JOIN ledger_accounts ala ON ala.id = ap.ledger_account_id AND ala.account_id IS NOT NULL
+WHERE (
+    SELECT COUNT(*)
+    FROM postings sp
+    JOIN ledger_accounts sla ON sla.id = sp.ledger_account_id
+    WHERE sp.journal_entry_id = cp.journal_entry_id AND sla.category_id IS NOT NULL
+) > 1;
@@ -0,0 +1 @@
+ALTER TABLE recurring_rules DROP COLUMN IF EXISTS failures;
@@ -0,0 +1 @@
+ALTER TABLE recurring_rules ADD COLUMN failures integer NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+-- Access tokens carry the version current when they were issued; raising it
+-- revokes them all. password_changed_at only has second precision in the
+-- tokens' iat claim, which let tokens of the same second outlive a change.
+ALTER TABLE users ADD COLUMN token_version integer NOT NULL DEFAULT 0;
@@ -0,0 +1,135 @@
+package seed
+
+import (
+	_ "embed"
+	"encoding/json"
+	"go-finance-tracker/internal/models"
+	"go-finance-tracker/pkg/logger"
+	"gorm.io/gorm"
+	"time"
+)
+
+//go:embed seed.json
+var seedFile []byte
+
+// Data is the reference data every installation needs: roles and their
+// permissions, transaction types and the system-wide default categories.
+type Data struct {
+	Version          int                            `json:"version"`
+	Roles            []string                       `json:"roles"`
+	RolePermissions  map[string][]string            `json:"rolePermissions"`
+	TransactionTypes []models.TransactionStatusType `json:"transactionTypes"`
+	Categories       []Category                     `json:"categories"`
+}
+
+type Category struct {
+	Name     string     `json:"name"`
+	Children []Category `json:"children"`
+}
+
+// appliedSeed records which seed file versions have been applied.
+type appliedSeed struct {
+	Version   int `gorm:"primaryKey;autoIncrement:false"`
+	AppliedAt time.Time
+}
+
+func (appliedSeed) TableName() string {
+	return "seed_versions"
+}
+
+func Load() (*Data, error) {
+	var data Data
+	if err := json.Unmarshal(seedFile, &data); err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// Run upserts the embedded reference data. It is skipped when the embedded
+// version has already been applied unless force is set; every step is an
+// upsert, so running it again is always safe.
+func Run(db *gorm.DB, force bool) error {
+	data, err := Load()
+	if err != nil {
+		return err
+	}
+
+	var latest int
+	if err := db.Model(&appliedSeed{}).Select("COALESCE(MAX(version), 0)").Scan(&latest).Error; err != nil {
+		return err
+	}
+	if latest >= data.Version && !force {
+		logger.GetLogger().Infof("Seed data is up to date (version %d)", latest)
+		return nil
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for _, name := range data.Roles {
+			role := models.Role{Name: name}
+			if err := tx.Where("name = ?", name).FirstOrCreate(&role).Error; err != nil {
+				return err
+			}
+			if err := grantPermissions(tx, &role, data.RolePermissions[name]); err != nil {
+				return err
+			}
+		}
+
+		for _, name := range data.TransactionTypes {
+			transactionType := models.TransactionType{Name: name}
+			if err := tx.Where("name = ?", name).FirstOrCreate(&transactionType).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := upsertCategories(tx, data.Categories, nil); err != nil {
+			return err
+		}
+
+		applied := appliedSeed{Version: data.Version, AppliedAt: time.Now()}
+		return tx.Where("version = ?", data.Version).FirstOrCreate(&applied).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.GetLogger().Infof("👍 Seed data applied (version %d)", data.Version)
+	return nil
+}
+
+// grantPermissions upserts the named permissions and adds the missing ones
+// to role. Permissions granted outside the seed file are left alone.
+func grantPermissions(tx *gorm.DB, role *models.Role, names []string) error {
+	if len(names) == 0 {
+		return nil
+	}
+
+	permissions := make([]models.Permission, len(names))
+	for i, name := range names {
+		permissions[i].Name = name
+		if err := tx.Where("name = ?", name).FirstOrCreate(&permissions[i]).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Model(role).Association("Permissions").Append(&permissions)
+}
+
+func upsertCategories(tx *gorm.DB, categories []Category, parentID *uint) error {
+	for _, c := range categories {
+		category := models.Category{Name: c.Name, ParentID: parentID}
+
+		query := tx.Where("user_id IS NULL AND name = ?", c.Name)
+		if parentID == nil {
+			query = query.Where("parent_id IS NULL")
+		} else {
+			query = query.Where("parent_id = ?", *parentID)
+		}
+		if err := query.FirstOrCreate(&category).Error; err != nil {
+			return err
+		}
+
+		if err := upsertCategories(tx, c.Children, &category.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
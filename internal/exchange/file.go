@@ -0,0 +1,87 @@
+package exchange
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"go-finance-tracker/internal/models"
+	"go-finance-tracker/pkg/money"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+// FileProvider reads rates from a CSV file with the columns date, from, to
+// and rate, e.g. "2024-03-01,EUR,USD,1.0834". A header row and lines
+// starting with "#" are skipped. It stands in for a real rate feed: any job
+// that can write the file keeps the rates up to date.
+type FileProvider struct {
+	Path string
+}
+
+func NewFileProvider(path string) *FileProvider {
+	return &FileProvider{Path: path}
+}
+
+func (p *FileProvider) Rates(_ context.Context) ([]models.ExchangeRate, error) {
+	file, err := os.Open(p.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return ReadCSV(file)
+}
+
+// ReadCSV parses rates in the format of FileProvider.
+func ReadCSV(r io.Reader) ([]models.ExchangeRate, error) {
+	reader := csv.NewReader(r)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = 4
+	reader.TrimLeadingSpace = true
+
+	rates := []models.ExchangeRate{}
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			return rates, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		line, _ := reader.FieldPos(0)
+		if line == 1 && strings.EqualFold(fields[0], "date") {
+			continue
+		}
+
+		rate, err := ParseRate(fields[0], fields[1], fields[2], fields[3])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		rates = append(rates, rate)
+	}
+}
+
+// ParseRate builds an exchange rate from its text fields, with the date in
+// the "2006-01-02" format.
+func ParseRate(date, from, to, rate string) (models.ExchangeRate, error) {
+	var exchangeRate models.ExchangeRate
+	var err error
+
+	if exchangeRate.Date, err = time.Parse(dateLayout, strings.TrimSpace(date)); err != nil {
+		return exchangeRate, err
+	}
+	if exchangeRate.FromCurrency, err = money.ParseCurrency(from); err != nil {
+		return exchangeRate, err
+	}
+	if exchangeRate.ToCurrency, err = money.ParseCurrency(to); err != nil {
+		return exchangeRate, err
+	}
+	if exchangeRate.Rate, err = money.ParseRate(strings.TrimSpace(rate)); err != nil {
+		return exchangeRate, err
+	}
+	return exchangeRate, nil
+}
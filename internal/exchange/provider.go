@@ -0,0 +1,65 @@
+package exchange
+
+import (
+	"context"
+	"go-finance-tracker/internal/models"
+	"go-finance-tracker/internal/repository"
+	"go-finance-tracker/pkg/logger"
+	"time"
+)
+
+// Provider is a source of exchange rates. Implementations may return rates
+// that are already stored; they are upserted, so syncing is idempotent.
+type Provider interface {
+	Rates(ctx context.Context) ([]models.ExchangeRate, error)
+}
+
+// Sync stores the rates of provider and returns how many it supplied.
+func Sync(ctx context.Context, provider Provider, exchangeRateRepo repository.ExchangeRateRepo) (int, error) {
+	rates, err := provider.Rates(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if err := exchangeRateRepo.Upsert(rates); err != nil {
+		return 0, err
+	}
+	return len(rates), nil
+}
+
+// Syncer periodically syncs the rates of a provider.
+type Syncer struct {
+	provider         Provider
+	exchangeRateRepo repository.ExchangeRateRepo
+	interval         time.Duration
+}
+
+func NewSyncer(provider Provider, exchangeRateRepo repository.ExchangeRateRepo, interval time.Duration) *Syncer {
+	return &Syncer{
+		provider:         provider,
+		exchangeRateRepo: exchangeRateRepo,
+		interval:         interval,
+	}
+}
+
+// Run blocks until ctx is cancelled.
+func (s *Syncer) Run(ctx context.Context) {
+	logger.GetLogger().Info("Exchange rate syncer started")
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		if count, err := Sync(ctx, s.provider, s.exchangeRateRepo); err != nil {
+			logger.GetLogger().Error("Failed to sync exchange rates:", err)
+		} else {
+			logger.GetLogger().Infof("Synced %d exchange rate(s)", count)
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.GetLogger().Info("Exchange rate syncer stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
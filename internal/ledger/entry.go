@@ -0,0 +1,42 @@
+// Package ledger holds the double-entry rules shared by the repository and
+// the API: when a journal entry is balanced and how account balances add up
+// to a trial balance and a balance sheet.
+package ledger
+
+import (
+	"errors"
+	"go-finance-tracker/internal/models"
+	"go-finance-tracker/pkg/money"
+)
+
+var (
+	ErrTooFewPostings = errors.New("a journal entry needs at least two postings")
+	ErrZeroPosting    = errors.New("postings must have a non-zero amount")
+	ErrNoCurrency     = errors.New("postings must have a currency")
+	ErrUnbalanced     = errors.New("postings of a journal entry must sum to zero in every currency")
+)
+
+// Validate checks that the postings form a balanced entry: at least two
+// non-zero postings whose debits and credits cancel out per currency.
+func Validate(postings []models.Posting) error {
+	if len(postings) < 2 {
+		return ErrTooFewPostings
+	}
+
+	sums := map[money.Currency]money.Amount{}
+	for _, posting := range postings {
+		if posting.Amount.IsZero() {
+			return ErrZeroPosting
+		}
+		if posting.Currency == "" {
+			return ErrNoCurrency
+		}
+		sums[posting.Currency] = sums[posting.Currency].Add(posting.Amount)
+	}
+	for _, sum := range sums {
+		if !sum.IsZero() {
+			return ErrUnbalanced
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package ledger
+
+import (
+	"errors"
+	"go-finance-tracker/internal/models"
+	"go-finance-tracker/pkg/money"
+	"testing"
+)
+
+func posting(account uint, amount string, currency money.Currency) models.Posting {
+	return models.Posting{LedgerAccountID: account, Amount: money.MustParse(amount), Currency: currency}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		postings []models.Posting
+		want     error
+	}{
+		{
+			name:     "no postings",
+			postings: nil,
+			want:     ErrTooFewPostings,
+		},
+		{
+			name:     "single posting",
+			postings: []models.Posting{posting(1, "10", "USD")},
+			want:     ErrTooFewPostings,
+		},
+		{
+			name:     "zero posting",
+			postings: []models.Posting{posting(1, "10", "USD"), posting(2, "-10", "USD"), posting(3, "0", "USD")},
+			want:     ErrZeroPosting,
+		},
+		{
+			name:     "missing currency",
+			postings: []models.Posting{posting(1, "10", "USD"), posting(2, "-10", "")},
+			want:     ErrNoCurrency,
+		},
+		{
+			name:     "unbalanced",
+			postings: []models.Posting{posting(1, "10", "USD"), posting(2, "-9.99", "USD")},
+			want:     ErrUnbalanced,
+		},
+		{
+			name: "balanced in one currency but not another",
+			postings: []models.Posting{
+				posting(1, "10", "USD"), posting(2, "-10", "USD"),
+				posting(3, "5", "EUR"), posting(4, "-4", "EUR"),
+			},
+			want: ErrUnbalanced,
+		},
+		{
+			name:     "amounts cancel out across currencies only",
+			postings: []models.Posting{posting(1, "10", "USD"), posting(2, "-10", "EUR")},
+			want:     ErrUnbalanced,
+		},
+		{
+			name:     "balanced",
+			postings: []models.Posting{posting(1, "10", "USD"), posting(2, "-10", "USD")},
+		},
+		{
+			name: "split over several accounts",
+			postings: []models.Posting{
+				posting(1, "-100", "USD"), posting(2, "60.50", "USD"), posting(3, "39.50", "USD"),
+			},
+		},
+		{
+			name: "balanced in every currency",
+			postings: []models.Posting{
+				posting(1, "10", "USD"), posting(2, "-10", "USD"),
+				posting(3, "5", "EUR"), posting(4, "-5", "EUR"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Validate(tt.postings); !errors.Is(err, tt.want) {
+				t.Errorf("Validate = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
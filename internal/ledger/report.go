@@ -0,0 +1,179 @@
+package ledger
+
+import (
+	"go-finance-tracker/internal/models"
+	"go-finance-tracker/pkg/money"
+	"sort"
+	"time"
+)
+
+// RetainedEarningsName labels the equity line of a balance sheet that holds
+// income less expenses, which are not closed into equity accounts.
+const RetainedEarningsName = "Retained Earnings"
+
+// Balance is the sum of the postings of one ledger account in one currency,
+// debits positive.
+type Balance struct {
+	LedgerAccountID uint
+	Name            string
+	Type            models.LedgerAccountType
+	Currency        money.Currency
+	Total           money.Amount
+}
+
+// TrialBalanceLine shows the balance of an account on its debit or its
+// credit side.
+type TrialBalanceLine struct {
+	LedgerAccountID uint                     `json:"ledgerAccountID"`
+	Name            string                   `json:"name"`
+	Type            models.LedgerAccountType `json:"type"`
+	Currency        money.Currency           `json:"currency"`
+	Debit           money.Amount             `json:"debit"`
+	Credit          money.Amount             `json:"credit"`
+}
+
+type TrialBalanceTotal struct {
+	Currency money.Currency `json:"currency"`
+	Debit    money.Amount   `json:"debit"`
+	Credit   money.Amount   `json:"credit"`
+}
+
+// TrialBalance lists every account with a balance as of At. It is balanced
+// when debits equal credits in every currency, which the repository
+// guarantees for each entry.
+type TrialBalance struct {
+	At       time.Time           `json:"at"`
+	Lines    []TrialBalanceLine  `json:"lines"`
+	Totals   []TrialBalanceTotal `json:"totals"`
+	Balanced bool                `json:"balanced"`
+}
+
+func NewTrialBalance(at time.Time, balances []Balance) *TrialBalance {
+	trialBalance := &TrialBalance{
+		At:       at,
+		Lines:    []TrialBalanceLine{},
+		Totals:   []TrialBalanceTotal{},
+		Balanced: true,
+	}
+
+	totals := map[money.Currency]*TrialBalanceTotal{}
+	for _, balance := range balances {
+		if balance.Total.IsZero() {
+			continue
+		}
+		line := TrialBalanceLine{
+			LedgerAccountID: balance.LedgerAccountID,
+			Name:            balance.Name,
+			Type:            balance.Type,
+			Currency:        balance.Currency,
+		}
+		if balance.Total.IsPositive() {
+			line.Debit = balance.Total
+		} else {
+			line.Credit = balance.Total.Neg()
+		}
+		trialBalance.Lines = append(trialBalance.Lines, line)
+
+		total, ok := totals[balance.Currency]
+		if !ok {
+			total = &TrialBalanceTotal{Currency: balance.Currency}
+			totals[balance.Currency] = total
+		}
+		total.Debit = total.Debit.Add(line.Debit)
+		total.Credit = total.Credit.Add(line.Credit)
+	}
+
+	for _, total := range totals {
+		trialBalance.Totals = append(trialBalance.Totals, *total)
+		if total.Debit != total.Credit {
+			trialBalance.Balanced = false
+		}
+	}
+	sort.Slice(trialBalance.Totals, func(i, j int) bool {
+		return trialBalance.Totals[i].Currency < trialBalance.Totals[j].Currency
+	})
+	return trialBalance
+}
+
+// BalanceSheetLine is the balance of an account in its normal direction, so
+// that a liability the user owes is positive.
+type BalanceSheetLine struct {
+	LedgerAccountID uint         `json:"ledgerAccountID,omitempty"`
+	Name            string       `json:"name"`
+	Balance         money.Amount `json:"balance"`
+}
+
+// CurrencyBalanceSheet holds the balance sheet of one currency. Assets
+// always equal liabilities plus equity.
+type CurrencyBalanceSheet struct {
+	Currency         money.Currency     `json:"currency"`
+	Assets           []BalanceSheetLine `json:"assets"`
+	Liabilities      []BalanceSheetLine `json:"liabilities"`
+	Equity           []BalanceSheetLine `json:"equity"`
+	TotalAssets      money.Amount       `json:"totalAssets"`
+	TotalLiabilities money.Amount       `json:"totalLiabilities"`
+	TotalEquity      money.Amount       `json:"totalEquity"`
+}
+
+// BalanceSheet reports the position as of At, one sheet per currency since
+// the ledger does not convert between them.
+type BalanceSheet struct {
+	At         time.Time              `json:"at"`
+	Currencies []CurrencyBalanceSheet `json:"currencies"`
+}
+
+// NewBalanceSheet builds the balance sheet from account balances. Income and
+// expense accounts are summed into a retained earnings line of equity.
+func NewBalanceSheet(at time.Time, balances []Balance) *BalanceSheet {
+	sheets := map[money.Currency]*CurrencyBalanceSheet{}
+	earnings := map[money.Currency]money.Amount{}
+
+	for _, balance := range balances {
+		if balance.Total.IsZero() {
+			continue
+		}
+		sheet, ok := sheets[balance.Currency]
+		if !ok {
+			sheet = &CurrencyBalanceSheet{
+				Currency:    balance.Currency,
+				Assets:      []BalanceSheetLine{},
+				Liabilities: []BalanceSheetLine{},
+				Equity:      []BalanceSheetLine{},
+			}
+			sheets[balance.Currency] = sheet
+		}
+
+		line := BalanceSheetLine{
+			LedgerAccountID: balance.LedgerAccountID,
+			Name:            balance.Name,
+			Balance:         balance.Total.Neg(),
+		}
+		switch balance.Type {
+		case models.LedgerAsset:
+			line.Balance = balance.Total
+			sheet.Assets = append(sheet.Assets, line)
+			sheet.TotalAssets = sheet.TotalAssets.Add(line.Balance)
+		case models.LedgerLiability:
+			sheet.Liabilities = append(sheet.Liabilities, line)
+			sheet.TotalLiabilities = sheet.TotalLiabilities.Add(line.Balance)
+		case models.LedgerEquity:
+			sheet.Equity = append(sheet.Equity, line)
+			sheet.TotalEquity = sheet.TotalEquity.Add(line.Balance)
+		case models.LedgerIncome, models.LedgerExpense:
+			earnings[balance.Currency] = earnings[balance.Currency].Add(line.Balance)
+		}
+	}
+
+	balanceSheet := &BalanceSheet{At: at, Currencies: []CurrencyBalanceSheet{}}
+	for currency, sheet := range sheets {
+		if retained := earnings[currency]; !retained.IsZero() {
+			sheet.Equity = append(sheet.Equity, BalanceSheetLine{Name: RetainedEarningsName, Balance: retained})
+			sheet.TotalEquity = sheet.TotalEquity.Add(retained)
+		}
+		balanceSheet.Currencies = append(balanceSheet.Currencies, *sheet)
+	}
+	sort.Slice(balanceSheet.Currencies, func(i, j int) bool {
+		return balanceSheet.Currencies[i].Currency < balanceSheet.Currencies[j].Currency
+	})
+	return balanceSheet
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"go-finance-tracker/pkg/money"
+	"gorm.io/gorm"
+)
+
+type AccountType string
+
+const (
+	AccountChecking   AccountType = "CHECKING"
+	AccountSavings    AccountType = "SAVINGS"
+	AccountCreditCard AccountType = "CREDIT_CARD"
+	AccountCash       AccountType = "CASH"
+	AccountOther      AccountType = "OTHER"
+)
+
+// LedgerType is the type of the ledger account that mirrors accounts of
+// type t: money on a credit card is owed, everything else is held.
+func (t AccountType) LedgerType() LedgerAccountType {
+	if t == AccountCreditCard {
+		return LedgerLiability
+	}
+	return LedgerAsset
+}
+
+// DefaultAccountName is the account created for every new user, and the one
+// existing finance records were moved into.
+const DefaultAccountName = "Main"
+
+// Account is where a user's money is held, e.g. a checking account or a
+// credit card. Every finance record belongs to one account. Archived
+// accounts are kept for their history but take no new records.
+type Account struct {
+	gorm.Model
+	UserID         uint           `gorm:"index;not null" json:"userID"`
+	Name           string         `gorm:"size:64;not null" json:"name"`
+	Type           AccountType    `gorm:"size:16;not null" json:"type"`
+	Currency       money.Currency `gorm:"size:3;not null" json:"currency"`
+	OpeningBalance money.Amount   `gorm:"not null;default:0" json:"openingBalance"`
+	Archived       bool           `gorm:"not null;default:false" json:"archived"`
+	// Balance is the opening balance plus the account's finance records. It
+	// is derived when the account is read and never stored.
+	Balance money.Amount `gorm:"-" json:"balance"`
+}
+
+// NewDefaultAccount returns the account every user starts with.
+func NewDefaultAccount(userID uint) Account {
+	return Account{
+		UserID:   userID,
+		Name:     DefaultAccountName,
+		Type:     AccountChecking,
+		Currency: money.DefaultCurrency,
+	}
+}
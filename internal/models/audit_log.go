@@ -0,0 +1,62 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+type AuditAction string
+
+const (
+	AuditUserUpdated         AuditAction = "USER_UPDATED"
+	AuditRoleAssigned        AuditAction = "ROLE_ASSIGNED"
+	AuditRoleRevoked         AuditAction = "ROLE_REVOKED"
+	AuditUserLocked          AuditAction = "USER_LOCKED"
+	AuditUserUnlocked        AuditAction = "USER_UNLOCKED"
+	AuditUserDeleted         AuditAction = "USER_DELETED"
+	AuditUserRestored        AuditAction = "USER_RESTORED"
+	AuditPasswordResetForced AuditAction = "PASSWORD_RESET_FORCED"
+	AuditMFAReset            AuditAction = "MFA_RESET"
+)
+
+// AuditLog records an action an administrator took on a user account.
+// Entries are only ever inserted.
+type AuditLog struct {
+	ID           uint         `gorm:"primaryKey" json:"id"`
+	CreatedAt    time.Time    `json:"createdAt"`
+	ActorID      uint         `gorm:"index;not null" json:"actorID"`
+	Action       AuditAction  `gorm:"size:32;not null" json:"action"`
+	TargetUserID uint         `gorm:"index;not null" json:"targetUserID"`
+	IP           string       `gorm:"size:45" json:"ip"`
+	Details      AuditDetails `gorm:"type:jsonb" json:"details,omitempty"`
+}
+
+// AuditDetails holds action specific data, e.g. the fields that changed.
+type AuditDetails map[string]any
+
+func (d AuditDetails) Value() (driver.Value, error) {
+	if d == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	return string(raw), nil
+}
+
+func (d *AuditDetails) Scan(value any) error {
+	switch v := value.(type) {
+	case nil:
+		*d = nil
+		return nil
+	case []byte:
+		return json.Unmarshal(v, d)
+	case string:
+		return json.Unmarshal([]byte(v), d)
+	default:
+		return errors.New("unsupported audit details type")
+	}
+}
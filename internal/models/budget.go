@@ -0,0 +1,86 @@
+package models
+
+import (
+	"go-finance-tracker/pkg/money"
+	"gorm.io/gorm"
+	"time"
+)
+
+type BudgetPeriodType string
+
+const (
+	BudgetWeekly  BudgetPeriodType = "WEEKLY"
+	BudgetMonthly BudgetPeriodType = "MONTHLY"
+	BudgetYearly  BudgetPeriodType = "YEARLY"
+)
+
+// Budget caps the expenses of a category (including its sub-categories) for
+// every period starting at StartDate. Amount is in the user's base currency.
+// With Rollover the unspent part of a period is added to the next one.
+type Budget struct {
+	gorm.Model
+	UserID     uint             `gorm:"index;not null" json:"userID"`
+	CategoryID uint             `gorm:"not null" json:"categoryID"`
+	Category   Category         `gorm:"foreignKey:CategoryID" json:"-"`
+	PeriodType BudgetPeriodType `gorm:"size:16;not null" json:"periodType"`
+	Amount     money.Amount     `gorm:"not null" json:"amount"`
+	StartDate  time.Time        `gorm:"type:date;not null" json:"startDate"`
+	Rollover   bool             `gorm:"not null;default:false" json:"rollover"`
+}
+
+// PeriodStart returns the start of the i-th period in loc. Monthly and
+// yearly periods keep the day of StartDate, clamped to the length of shorter
+// months (a budget starting on the 31st renews on Feb 28/29).
+func (b *Budget) PeriodStart(i int, loc *time.Location) time.Time {
+	year, month, day := b.StartDate.Date()
+	switch b.PeriodType {
+	case BudgetWeekly:
+		return time.Date(year, month, day+7*i, 0, 0, 0, 0, loc)
+	case BudgetYearly:
+		return clampedDate(year+i, month, day, loc)
+	default:
+		return clampedDate(year, month+time.Month(i), day, loc)
+	}
+}
+
+// PeriodIndexAt returns the index of the period containing t, or -1 when t
+// is before StartDate.
+func (b *Budget) PeriodIndexAt(t time.Time, loc *time.Location) int {
+	t = t.In(loc)
+	if t.Before(b.PeriodStart(0, loc)) {
+		return -1
+	}
+
+	startYear, startMonth, startDay := b.StartDate.Date()
+	year, month, day := t.Date()
+
+	var i int
+	switch b.PeriodType {
+	case BudgetWeekly:
+		start := time.Date(startYear, startMonth, startDay, 0, 0, 0, 0, time.UTC)
+		current := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		i = int(current.Sub(start).Hours()/24) / 7
+	case BudgetYearly:
+		i = year - startYear
+	default:
+		i = (year-startYear)*12 + int(month-startMonth)
+	}
+
+	for i > 0 && b.PeriodStart(i, loc).After(t) {
+		i--
+	}
+	for !b.PeriodStart(i+1, loc).After(t) {
+		i++
+	}
+	return i
+}
+
+func clampedDate(year int, month time.Month, day int, loc *time.Location) time.Time {
+	// Normalize month overflow first, then clamp the day to the month length.
+	first := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	lastDay := first.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+	return time.Date(first.Year(), first.Month(), day, 0, 0, 0, 0, loc)
+}
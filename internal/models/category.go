@@ -2,8 +2,21 @@ package models
 
 import "gorm.io/gorm"
 
+// TransferCategoryName is the system category transfers between accounts
+// are filed under.
+const TransferCategoryName = "Transfers"
+
+// Category groups finance records. Categories without a UserID are
+// system-wide defaults visible to everyone; the rest belong to one user.
 type Category struct {
 	gorm.Model
-	Name           string
-	FinanceRecords []FinanceRecord
+	Name           string          `gorm:"size:64;not null" json:"name"`
+	UserID         *uint           `gorm:"index" json:"userID"`
+	ParentID       *uint           `gorm:"index" json:"parentID"`
+	Children       []Category      `gorm:"foreignKey:ParentID" json:"children,omitempty"`
+	FinanceRecords []FinanceRecord `json:"-"`
+}
+
+func (c *Category) IsSystem() bool {
+	return c.UserID == nil
 }
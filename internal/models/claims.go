@@ -6,4 +6,37 @@ type Claims struct {
 	jwt.StandardClaims
 	Id       string `json:"id"`
 	Username string `json:"username"`
+	// SessionID is the session the token was issued for.
+	SessionID uint `json:"sid,omitempty"`
+	// TokenVersion is the user's token version when the token was issued.
+	TokenVersion int `json:"ver,omitempty"`
+	// Roles and Permissions are copied from the database when the token is
+	// issued, so changes apply from the next refresh.
+	Roles       []string `json:"roles,omitempty"`
+	Permissions []string `json:"permissions,omitempty"`
+	// Purpose is empty for access tokens. Tokens issued for another purpose,
+	// such as finishing a two-factor login, are not accepted as access
+	// tokens.
+	Purpose string `json:"purpose,omitempty"`
+}
+
+// MFAPendingPurpose marks a token that proves the password was checked and
+// can only be exchanged for a session together with a second factor.
+const MFAPendingPurpose = "mfa_pending"
+
+func (c *Claims) HasRole(name string) bool {
+	return contains(c.Roles, name)
+}
+
+func (c *Claims) HasPermission(name string) bool {
+	return contains(c.Permissions, name)
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
 }
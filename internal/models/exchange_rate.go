@@ -0,0 +1,19 @@
+package models
+
+import (
+	"go-finance-tracker/pkg/money"
+	"time"
+)
+
+// ExchangeRate is the rate for converting FromCurrency into ToCurrency on
+// Date: one unit of FromCurrency buys Rate units of ToCurrency. There is at
+// most one rate per pair and day.
+type ExchangeRate struct {
+	ID           uint           `gorm:"primaryKey" json:"id"`
+	CreatedAt    time.Time      `json:"createdAt"`
+	UpdatedAt    time.Time      `json:"updatedAt"`
+	Date         time.Time      `gorm:"type:date;not null" json:"date"`
+	FromCurrency money.Currency `gorm:"size:3;not null" json:"from"`
+	ToCurrency   money.Currency `gorm:"size:3;not null" json:"to"`
+	Rate         money.Rate     `gorm:"not null" json:"rate"`
+}
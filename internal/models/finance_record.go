@@ -1,14 +1,36 @@
 package models
 
-import "gorm.io/gorm"
+import (
+	"go-finance-tracker/pkg/money"
+	"gorm.io/gorm"
+)
 
+// FinanceRecord is a single income, expense or half of a transfer, in the
+// currency of its account. Amounts are positive, except that the half of a
+// transfer leaving an account is negative.
+//
+// Records are a read-only view of the journal: each is the posting of a
+// journal entry to an account, and takes its ID from it. They are written
+// through the finance and transfer repositories, which book the entry.
 type FinanceRecord struct {
 	gorm.Model
 	UserID            uint            `gorm:"index" json:"userID"`
-	Amount            float64         `json:"amount"`
+	AccountID         uint            `gorm:"index;not null" json:"accountID"`
+	Amount            money.Amount    `json:"amount"`
+	Currency          money.Currency  `gorm:"size:3;not null" json:"currency"`
 	TransactionTypeID uint            `json:"transactionTypeID"`
 	TransactionType   TransactionType `gorm:"foreignKey:TransactionTypeID"`
 	CategoryID        uint            `json:"categoryID"`
 	Category          Category        `gorm:"foreignKey:CategoryID"`
 	Note              string          `json:"note"`
+	RecurringRuleID   *uint           `json:"recurringRuleID,omitempty"`
+	Occurrence        *int            `json:"-"`
+	// TransferRecordID links the two halves of a transfer to each other.
+	TransferRecordID *uint `json:"transferRecordID,omitempty"`
+	// Splits divides the amount between several categories; CategoryID is
+	// then the category of the first line, which the record is listed under.
+	Splits []FinanceSplit `gorm:"foreignKey:FinanceRecordID" json:"splits,omitempty"`
+	// JournalEntryID is the entry the record is read from; both halves of a
+	// transfer share one.
+	JournalEntryID uint `gorm:"->" json:"journalEntryID"`
 }
@@ -0,0 +1,15 @@
+package models
+
+import "go-finance-tracker/pkg/money"
+
+// FinanceSplit is one line of a split record: the part of its amount that
+// belongs to Category. The lines of a record sum to the record's amount, and
+// reports and budgets count the lines instead of the record's own category.
+// Each line is a posting to the ledger account of its category.
+type FinanceSplit struct {
+	ID              uint         `gorm:"primaryKey" json:"id"`
+	FinanceRecordID uint         `gorm:"index;not null" json:"financeRecordID"`
+	CategoryID      uint         `gorm:"index;not null" json:"categoryID"`
+	Amount          money.Amount `gorm:"not null" json:"amount"`
+	Note            string       `json:"note"`
+}
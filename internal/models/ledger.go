@@ -0,0 +1,83 @@
+package models
+
+import (
+	"go-finance-tracker/pkg/money"
+	"time"
+)
+
+type LedgerAccountType string
+
+const (
+	LedgerAsset     LedgerAccountType = "ASSET"
+	LedgerLiability LedgerAccountType = "LIABILITY"
+	LedgerIncome    LedgerAccountType = "INCOME"
+	LedgerExpense   LedgerAccountType = "EXPENSE"
+	LedgerEquity    LedgerAccountType = "EQUITY"
+)
+
+// OpeningBalancesName is the equity account opening balances of accounts are
+// booked against.
+const OpeningBalancesName = "Opening Balances"
+
+func (t LedgerAccountType) Valid() bool {
+	switch t {
+	case LedgerAsset, LedgerLiability, LedgerIncome, LedgerExpense, LedgerEquity:
+		return true
+	}
+	return false
+}
+
+// DebitNormal reports whether debits increase accounts of the type.
+func (t LedgerAccountType) DebitNormal() bool {
+	return t == LedgerAsset || t == LedgerExpense
+}
+
+// LedgerAccount is an account of the user's chart of accounts. Every Account
+// has one (a liability for credit cards, an asset otherwise), and every
+// category gets an income or expense one when a record first needs it.
+// Accounts linked to neither are opened by the user, e.g. for a loan.
+type LedgerAccount struct {
+	ID         uint              `gorm:"primaryKey" json:"id"`
+	CreatedAt  time.Time         `json:"createdAt"`
+	UpdatedAt  time.Time         `json:"updatedAt"`
+	UserID     uint              `gorm:"index;not null" json:"userID"`
+	Name       string            `gorm:"size:128;not null" json:"name"`
+	Type       LedgerAccountType `gorm:"size:16;not null" json:"type"`
+	AccountID  *uint             `json:"accountID,omitempty"`
+	CategoryID *uint             `json:"categoryID,omitempty"`
+}
+
+// IsLinked reports whether the account mirrors an Account or a category, in
+// which case only finance records post to it.
+func (a *LedgerAccount) IsLinked() bool {
+	return a.AccountID != nil || a.CategoryID != nil
+}
+
+// JournalEntry is a balanced set of postings made at Date: in every currency
+// its amounts sum to zero. It is where income, expenses and transfers are
+// stored; finance records are read from the entries posting to Accounts.
+// Those entries and the opening balances of accounts are maintained through
+// the finance API and cannot be changed on their own.
+type JournalEntry struct {
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	CreatedAt        time.Time `json:"createdAt"`
+	UpdatedAt        time.Time `json:"updatedAt"`
+	UserID           uint      `gorm:"index;not null" json:"userID"`
+	Date             time.Time `gorm:"not null" json:"date"`
+	Description      string    `json:"description"`
+	OpeningAccountID *uint     `json:"openingAccountID,omitempty"`
+	RecurringRuleID  *uint     `json:"recurringRuleID,omitempty"`
+	Occurrence       *int      `json:"-"`
+	Postings         []Posting `gorm:"constraint:OnDelete:CASCADE" json:"postings"`
+}
+
+// Posting moves Amount into a ledger account: debits are positive and
+// credits negative.
+type Posting struct {
+	ID              uint           `gorm:"primaryKey" json:"id"`
+	JournalEntryID  uint           `gorm:"index;not null" json:"journalEntryID"`
+	LedgerAccountID uint           `gorm:"index;not null" json:"ledgerAccountID"`
+	Amount          money.Amount   `gorm:"not null" json:"amount"`
+	Currency        money.Currency `gorm:"size:3;not null" json:"currency"`
+	Note            string         `json:"note,omitempty"`
+}
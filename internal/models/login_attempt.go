@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// LoginAttempt records a login, successful or not, for throttling by client
+// IP and for investigating attacks.
+type LoginAttempt struct {
+	ID        uint      `gorm:"primaryKey"`
+	CreatedAt time.Time `gorm:"not null"`
+	Username  string    `gorm:"size:35;not null"`
+	UserID    *uint     `gorm:"index"`
+	IP        string    `gorm:"size:45;not null"`
+	Succeeded bool      `gorm:"not null"`
+}
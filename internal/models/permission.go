@@ -0,0 +1,19 @@
+package models
+
+import "gorm.io/gorm"
+
+// Permissions checked by middleware.RequirePermission. Roles are granted
+// permissions through the seed file.
+const (
+	PermissionUsersRead         = "users:read"
+	PermissionUsersWrite        = "users:write"
+	PermissionAuditRead         = "audit:read"
+	PermissionBalancesRecompute = "balances:recompute"
+	PermissionRatesWrite        = "rates:write"
+)
+
+type Permission struct {
+	gorm.Model
+	Name  string `gorm:"size:64;not null"`
+	Roles []Role `gorm:"many2many:role_permissions"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// RecoveryCode is a single-use code that replaces a TOTP code when the
+// authenticator is lost. Only the SHA-256 hash of the code is stored.
+type RecoveryCode struct {
+	ID        uint `gorm:"primaryKey"`
+	CreatedAt time.Time
+	UserID    uint   `gorm:"index;not null"`
+	CodeHash  string `gorm:"size:64;not null"`
+	UsedAt    *time.Time
+}
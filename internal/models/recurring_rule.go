@@ -0,0 +1,114 @@
+package models
+
+import (
+	"go-finance-tracker/pkg/money"
+	"gorm.io/gorm"
+	"time"
+)
+
+type RecurrenceFrequency string
+
+const (
+	RecurDaily   RecurrenceFrequency = "DAILY"
+	RecurWeekly  RecurrenceFrequency = "WEEKLY"
+	RecurMonthly RecurrenceFrequency = "MONTHLY"
+	RecurYearly  RecurrenceFrequency = "YEARLY"
+)
+
+// RecurringRule materializes a FinanceRecord every Interval days, weeks,
+// months or years starting at StartDate, until EndDate or Count occurrences.
+// Occurrences are computed in Timezone so they keep their local wall-clock
+// time across DST changes.
+type RecurringRule struct {
+	gorm.Model
+	UserID            uint                `gorm:"index;not null" json:"userID"`
+	AccountID         uint                `gorm:"not null" json:"accountID"`
+	Amount            money.Amount        `gorm:"not null" json:"amount"`
+	TransactionTypeID uint                `gorm:"not null" json:"transactionTypeID"`
+	CategoryID        uint                `json:"categoryID"`
+	Note              string              `json:"note"`
+	Frequency         RecurrenceFrequency `gorm:"size:16;not null" json:"frequency"`
+	Interval          int                 `gorm:"not null;default:1" json:"interval"`
+	DayOfMonth        int                 `gorm:"not null;default:0" json:"dayOfMonth"`
+	Timezone          string              `gorm:"size:64;not null" json:"timezone"`
+	StartDate         time.Time           `gorm:"not null" json:"startDate"`
+	EndDate           *time.Time          `json:"endDate"`
+	Count             *int                `json:"count"`
+	NextOccurrence    int                 `gorm:"not null;default:0" json:"nextOccurrence"`
+	NextRunAt         *time.Time          `gorm:"index" json:"nextRunAt"`
+
+	// Failures counts the consecutive runs that failed to materialize the
+	// rule. Each one postpones NextRunAt further; editing the rule or a
+	// successful run resets it.
+	Failures int `gorm:"not null;default:0" json:"failures"`
+}
+
+func (r *RecurringRule) Location() *time.Location {
+	loc, err := time.LoadLocation(r.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// OccurrenceAt returns the time of the i-th occurrence (starting at 0) and
+// false once the rule has ended. Monthly and yearly rules fall on DayOfMonth
+// (or the day of StartDate when zero), clamped to the length of the month.
+func (r *RecurringRule) OccurrenceAt(i int) (time.Time, bool) {
+	if i < 0 || r.Count != nil && i >= *r.Count {
+		return time.Time{}, false
+	}
+
+	loc := r.Location()
+	start := r.StartDate.In(loc)
+	year, month, day := start.Date()
+	hour, minute, second := start.Clock()
+	step := i * r.Interval
+
+	dayOfMonth := r.DayOfMonth
+	if dayOfMonth == 0 {
+		dayOfMonth = day
+	}
+
+	var date time.Time
+	switch r.Frequency {
+	case RecurDaily:
+		date = time.Date(year, month, day+step, 0, 0, 0, 0, loc)
+	case RecurWeekly:
+		date = time.Date(year, month, day+7*step, 0, 0, 0, 0, loc)
+	case RecurYearly:
+		date = clampedDate(year+step, month, dayOfMonth, loc)
+	default:
+		date = clampedDate(year, month+time.Month(step), dayOfMonth, loc)
+	}
+	occurrence := time.Date(date.Year(), date.Month(), date.Day(), hour, minute, second, 0, loc)
+
+	if r.EndDate != nil && occurrence.After(*r.EndDate) {
+		return time.Time{}, false
+	}
+	return occurrence, true
+}
+
+// Occurrences returns up to n occurrences starting at index from.
+func (r *RecurringRule) Occurrences(from, n int) []time.Time {
+	occurrences := make([]time.Time, 0, n)
+	for i := from; len(occurrences) < n; i++ {
+		occurrence, ok := r.OccurrenceAt(i)
+		if !ok {
+			break
+		}
+		occurrences = append(occurrences, occurrence)
+	}
+	return occurrences
+}
+
+// ScheduleNext sets NextRunAt from NextOccurrence, or clears it when the
+// rule has ended.
+func (r *RecurringRule) ScheduleNext() {
+	next, ok := r.OccurrenceAt(r.NextOccurrence)
+	if !ok {
+		r.NextRunAt = nil
+		return
+	}
+	r.NextRunAt = &next
+}
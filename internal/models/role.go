@@ -2,15 +2,14 @@ package models
 
 import "gorm.io/gorm"
 
-//type RoleType string
-//
-//const (
-//	USER  RoleType = "USER"
-//	ADMIN RoleType = "ADMIN"
-//)
+const (
+	RoleUser  = "USER"
+	RoleAdmin = "ADMIN"
+)
 
 type Role struct {
 	gorm.Model
-	Name  string `gorm:"size:35"`
-	Users []User `gorm:"many2many:user_roles"`
+	Name        string       `gorm:"size:35"`
+	Users       []User       `gorm:"many2many:user_roles"`
+	Permissions []Permission `gorm:"many2many:role_permissions"`
 }
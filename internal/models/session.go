@@ -0,0 +1,36 @@
+package models
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+// Session is one login on one device. Every refresh rotates the session's
+// refresh token; presenting a token that was already rotated revokes the
+// session, since it means the token was copied.
+type Session struct {
+	gorm.Model
+	UserID     uint       `gorm:"index;not null" json:"-"`
+	Device     string     `gorm:"size:64" json:"device"`
+	IP         string     `gorm:"size:45" json:"ip"`
+	UserAgent  string     `gorm:"size:255" json:"userAgent"`
+	LastUsedAt time.Time  `gorm:"not null" json:"lastUsedAt"`
+	ExpiresAt  time.Time  `gorm:"not null" json:"expiresAt"`
+	RevokedAt  *time.Time `json:"-"`
+
+	// Current marks the session of the requesting token in listings.
+	Current bool `gorm:"-" json:"current"`
+}
+
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// RefreshToken belongs to a session. Only the SHA-256 hash is stored, and a
+// used token is kept so that its reuse can be detected.
+type RefreshToken struct {
+	gorm.Model
+	SessionID uint   `gorm:"index;not null"`
+	TokenHash string `gorm:"size:64;uniqueIndex;not null"`
+	UsedAt    *time.Time
+}
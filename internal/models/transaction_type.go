@@ -7,6 +7,9 @@ type TransactionStatusType string
 const (
 	Income  TransactionStatusType = "INCOME"
 	Expense TransactionStatusType = "EXPENSE"
+	// Transfer moves money between two accounts of the same user. It is
+	// neither income nor spending.
+	Transfer TransactionStatusType = "TRANSFER"
 )
 
 type TransactionType struct {
@@ -1,19 +1,63 @@
 package models
 
 import (
+	"go-finance-tracker/pkg/money"
 	"gorm.io/gorm"
+	"time"
 )
 
 type User struct {
 	gorm.Model
-	Name       string  `gorm:"size:35" json:"name"`
-	Surname    string  `gorm:"size:35" json:"surname"`
-	Username   string  `gorm:"uniqueIndex;size:35;not null" json:"username"`
-	Email      string  `gorm:"uniqueIndex" json:"email"`
-	Password   string  `gorm:"type:varchar(255)" json:"-"`
-	TotalMoney float64 `json:"totalMoney"`
-	Roles      []Role  `gorm:"many2many:user_roles"`
+	Name       string       `gorm:"size:35" json:"name"`
+	Surname    string       `gorm:"size:35" json:"surname"`
+	Username   string       `gorm:"uniqueIndex;size:35;not null" json:"username"`
+	Email      string       `gorm:"uniqueIndex" json:"email"`
+	Password   string       `gorm:"type:varchar(255)" json:"-"`
+	TotalMoney money.Amount `json:"totalMoney"`
+	Roles      []Role       `gorm:"many2many:user_roles"`
+	// BaseCurrency is the currency reports are converted to.
+	BaseCurrency money.Currency `gorm:"size:3;not null;default:USD" json:"baseCurrency"`
+
+	// PasswordChangedAt is the time of the last password change, which also
+	// raises TokenVersion.
+	PasswordChangedAt *time.Time `json:"-"`
+	EmailVerifiedAt   *time.Time `json:"emailVerifiedAt"`
+	// LockedAt is set while an administrator has locked the account.
+	LockedAt *time.Time `json:"lockedAt"`
+	// FailedLogins counts consecutive failed logins; reaching the limit sets
+	// LockedUntil and starts the count again.
+	FailedLogins      int        `gorm:"not null;default:0" json:"-"`
+	LastFailedLoginAt *time.Time `json:"-"`
+	LockedUntil       *time.Time `json:"lockedUntil"`
+	// TOTPSecret is the encrypted secret for two-factor authentication. It
+	// is stored on enrollment and only used once TOTPEnabledAt is set.
+	TOTPSecret    string     `gorm:"size:255" json:"-"`
+	TOTPEnabledAt *time.Time `json:"totpEnabledAt"`
+	// TOTPLastStep is the time step of the last accepted code, so that a
+	// code cannot be used twice.
+	TOTPLastStep int64 `gorm:"not null;default:0" json:"-"`
+	// TokenVersion is copied into access tokens; raising it revokes every
+	// token issued before.
+	TokenVersion int `gorm:"not null;default:0" json:"-"`
 
 	// Define relationships
 	FinanceHistory []FinanceRecord `gorm:"foreignKey:UserID"`
 }
+
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+func (u *User) IsLocked() bool {
+	return u.LockedAt != nil
+}
+
+func (u *User) IsMFAEnabled() bool {
+	return u.TOTPEnabledAt != nil
+}
+
+// IsTemporarilyLocked reports whether too many failed logins have locked
+// the account at now.
+func (u *User) IsTemporarilyLocked(now time.Time) bool {
+	return u.LockedUntil != nil && now.Before(*u.LockedUntil)
+}
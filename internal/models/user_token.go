@@ -0,0 +1,24 @@
+package models
+
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+type TokenPurpose string
+
+const (
+	PasswordResetToken     TokenPurpose = "PASSWORD_RESET"
+	EmailVerificationToken TokenPurpose = "EMAIL_VERIFICATION"
+)
+
+// UserToken is a single-use token sent to a user by email. Only the SHA-256
+// hash of the token is stored.
+type UserToken struct {
+	gorm.Model
+	UserID    uint         `gorm:"index;not null"`
+	Purpose   TokenPurpose `gorm:"size:32;not null"`
+	TokenHash string       `gorm:"size:64;uniqueIndex;not null"`
+	ExpiresAt time.Time    `gorm:"not null"`
+	UsedAt    *time.Time
+}
@@ -0,0 +1,65 @@
+package recurring
+
+import (
+	"context"
+	"go-finance-tracker/internal/repository"
+	"go-finance-tracker/pkg/logger"
+	"time"
+)
+
+const defaultBatchSize = 50
+
+// Scheduler periodically materializes the due occurrences of recurring
+// rules. Every replica may run one; the repository makes concurrent runs
+// safe and restarts simply catch up on missed occurrences.
+type Scheduler struct {
+	recurringRepo repository.RecurringRepo
+	interval      time.Duration
+	batchSize     int
+}
+
+func NewScheduler(recurringRepo repository.RecurringRepo, interval time.Duration) *Scheduler {
+	return &Scheduler{
+		recurringRepo: recurringRepo,
+		interval:      interval,
+		batchSize:     defaultBatchSize,
+	}
+}
+
+// Run blocks until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	logger.GetLogger().Info("Recurring transaction scheduler started")
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.tick()
+
+		select {
+		case <-ctx.Done():
+			logger.GetLogger().Info("Recurring transaction scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick drains every due rule, one batch per transaction, until a batch
+// comes back short. Failing rules are postponed by the repository, so they
+// do not keep the loop going.
+func (s *Scheduler) tick() {
+	for {
+		claimed, created, err := s.recurringRepo.MaterializeDue(time.Now(), s.batchSize)
+		if err != nil {
+			logger.GetLogger().Error("Failed to materialize recurring transactions:", err)
+			return
+		}
+		if created > 0 {
+			logger.GetLogger().Infof("Materialized %d recurring transaction(s)", created)
+		}
+		if claimed < s.batchSize {
+			return
+		}
+	}
+}
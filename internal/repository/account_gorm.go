@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"errors"
+	"go-finance-tracker/internal/models"
+	"go-finance-tracker/pkg/money"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrAccountNotFound = errors.New("account not found")
+	ErrAccountArchived = errors.New("account is archived")
+	ErrAccountInUse    = errors.New("account still has finance records or recurring rules, archive it instead")
+	// ErrAccountCurrencyLocked is returned when changing the currency of an
+	// account whose records are already in the old one.
+	ErrAccountCurrencyLocked = errors.New("currency of an account with finance records cannot be changed")
+)
+
+type AccountRepository struct {
+	db *gorm.DB
+}
+
+func NewAccountRepository(db *gorm.DB) *AccountRepository {
+	return &AccountRepository{db: db}
+}
+
+// GetAll returns the user's accounts with their balances, leaving out
+// archived ones unless includeArchived is set.
+func (r *AccountRepository) GetAll(userID int, includeArchived bool) ([]models.Account, error) {
+	db := r.db.Where("user_id = ?", userID)
+	if !includeArchived {
+		db = db.Where("archived = false")
+	}
+
+	var accounts []models.Account
+	if err := db.Order("id").Find(&accounts).Error; err != nil {
+		return nil, err
+	}
+	if err := fillAccountBalances(r.db, userID, accounts); err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+func (r *AccountRepository) GetByID(userID int, id uint) (*models.Account, error) {
+	var account models.Account
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&account).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAccountNotFound
+		}
+		return nil, err
+	}
+
+	accounts := []models.Account{account}
+	if err := fillAccountBalances(r.db, userID, accounts); err != nil {
+		return nil, err
+	}
+	return &accounts[0], nil
+}
+
+// Resolve returns the account new records of userID should be booked to:
+// the account with the given id, or the user's oldest open account when id
+// is zero. Archived accounts are refused.
+func (r *AccountRepository) Resolve(userID int, id uint) (*models.Account, error) {
+	db := r.db.Where("user_id = ?", userID)
+	if id != 0 {
+		db = db.Where("id = ?", id)
+	} else {
+		db = db.Where("archived = false")
+	}
+
+	var account models.Account
+	if err := db.Order("id").First(&account).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAccountNotFound
+		}
+		return nil, err
+	}
+	if account.Archived {
+		return nil, ErrAccountArchived
+	}
+	return &account, nil
+}
+
+// Create stores the account together with its ledger account and opening
+// balance entry.
+func (r *AccountRepository) Create(account *models.Account) error {
+	if err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(account).Error; err != nil {
+			return err
+		}
+		return syncAccountLedger(tx, account)
+	}); err != nil {
+		return err
+	}
+	account.Balance = account.OpeningBalance
+	return nil
+}
+
+func (r *AccountRepository) Update(account *models.Account) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var current models.Account
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ?", account.ID, account.UserID).First(&current).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrAccountNotFound
+			}
+			return err
+		}
+
+		if account.Currency != current.Currency {
+			var count int64
+			if err := tx.Model(&models.FinanceRecord{}).Where("account_id = ?", account.ID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return ErrAccountCurrencyLocked
+			}
+		}
+
+		if err := tx.Model(&current).
+			Select("Name", "Type", "Currency", "OpeningBalance", "Archived").
+			Updates(account).Error; err != nil {
+			return err
+		}
+		account.CreatedAt = current.CreatedAt
+		return syncAccountLedger(tx, account)
+	})
+}
+
+// Delete removes an account that nothing refers to. Accounts with history
+// should be archived instead, so that the history keeps its account.
+func (r *AccountRepository) Delete(userID int, id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var account models.Account
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ?", id, userID).First(&account).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrAccountNotFound
+			}
+			return err
+		}
+
+		for _, model := range []any{&models.FinanceRecord{}, &models.RecurringRule{}} {
+			var count int64
+			if err := tx.Model(model).Where("account_id = ?", account.ID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return ErrAccountInUse
+			}
+		}
+
+		if err := tx.Delete(&account).Error; err != nil {
+			return err
+		}
+		return unlinkAccountLedger(tx, account.ID)
+	})
+}
+
+// accountCurrency returns the currency of the account, which its records
+// are booked in.
+func accountCurrency(tx *gorm.DB, accountID uint) (money.Currency, error) {
+	var account models.Account
+	if err := tx.Select("currency").First(&account, accountID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrAccountNotFound
+		}
+		return "", err
+	}
+	return account.Currency, nil
+}
+
+// fillAccountBalances sets the Balance of each account from the postings to
+// its ledger account, which include its opening balance.
+func fillAccountBalances(db *gorm.DB, userID int, accounts []models.Account) error {
+	var totals []struct {
+		AccountID uint
+		Total     money.Amount
+	}
+	if err := db.Raw(`
+		SELECT la.account_id, COALESCE(SUM(p.amount), 0) AS total
+		FROM postings p
+		JOIN ledger_accounts la ON la.id = p.ledger_account_id
+		WHERE la.user_id = ? AND la.account_id IS NOT NULL
+		GROUP BY la.account_id`,
+		userID,
+	).Scan(&totals).Error; err != nil {
+		return err
+	}
+
+	byAccount := make(map[uint]money.Amount, len(totals))
+	for _, total := range totals {
+		byAccount[total.AccountID] = total.Total
+	}
+	for i := range accounts {
+		accounts[i].Balance = byAccount[accounts[i].ID]
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"go-finance-tracker/internal/models"
+	"gorm.io/gorm"
+)
+
+// AuditQuery filters the audit log, newest entries first. Zero-valued
+// filters are ignored.
+type AuditQuery struct {
+	ActorID      *uint
+	TargetUserID *uint
+	Action       models.AuditAction
+	Cursor       string
+	Limit        int
+}
+
+type AuditPage struct {
+	Entries    []models.AuditLog `json:"entries"`
+	NextCursor string            `json:"nextCursor,omitempty"`
+}
+
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// recordAudit inserts entry in the transaction of the action it describes,
+// so that an action never succeeds without its entry. Actions the user takes
+// on their own account pass nil and are not audited.
+func recordAudit(tx *gorm.DB, entry *models.AuditLog) error {
+	if entry == nil {
+		return nil
+	}
+	return tx.Create(entry).Error
+}
+
+func (r *AuditRepository) Find(query AuditQuery) (*AuditPage, error) {
+	if query.Limit <= 0 {
+		query.Limit = DefaultUserPageSize
+	}
+	if query.Limit > MaxUserPageSize {
+		query.Limit = MaxUserPageSize
+	}
+
+	db := r.db.Model(&models.AuditLog{})
+	if query.ActorID != nil {
+		db = db.Where("actor_id = ?", *query.ActorID)
+	}
+	if query.TargetUserID != nil {
+		db = db.Where("target_user_id = ?", *query.TargetUserID)
+	}
+	if query.Action != "" {
+		db = db.Where("action = ?", query.Action)
+	}
+	if query.Cursor != "" {
+		beforeID, err := decodeIDCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		db = db.Where("id < ?", beforeID)
+	}
+
+	var entries []models.AuditLog
+	if err := db.Order("id DESC").Limit(query.Limit + 1).Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	page := &AuditPage{Entries: entries}
+	if len(entries) > query.Limit {
+		page.Entries = entries[:query.Limit]
+		page.NextCursor = encodeIDCursor(page.Entries[query.Limit-1].ID)
+	}
+	return page, nil
+}
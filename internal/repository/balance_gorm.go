@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"errors"
+	"go-finance-tracker/internal/models"
+	"go-finance-tracker/pkg/money"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// balanceTimezone decides the day whose exchange rate a record is converted
+// with for the stored balance.
+const balanceTimezone = "UTC"
+
+var (
+	ErrUnknownTransactionType = errors.New("unknown transaction type")
+	ErrTransferRecord         = errors.New("transfers can only be created through /v1/transfers and not edited")
+)
+
+// BalanceDrift reports a user whose stored TotalMoney differs from the sum of
+// their finance history.
+//
+// TotalMoney is kept in the user's base currency: every record is converted
+// with the rate of its UTC day, like reports, and records without a known
+// rate do not count until one is imported and the balances are recomputed.
+type BalanceDrift struct {
+	UserID   uint         `json:"userID"`
+	Username string       `json:"username"`
+	Stored   money.Amount `json:"stored"`
+	Computed money.Amount `json:"computed"`
+}
+
+type BalanceRepository struct {
+	db *gorm.DB
+}
+
+func NewBalanceRepository(db *gorm.DB) *BalanceRepository {
+	return &BalanceRepository{db: db}
+}
+
+// Recompute rebuilds every user's balance from their finance history and
+// returns the users whose stored balance had drifted. When fix is true the
+// stored balances are overwritten with the computed ones.
+func (r *BalanceRepository) Recompute(fix bool) ([]BalanceDrift, error) {
+	var drifts []BalanceDrift
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if fix {
+			// Lock the users first so that concurrent record writes, which
+			// adjust the balance after inserting, are either fully visible to
+			// the sum below or applied on top of the corrected balance.
+			var ids []uint
+			if err := tx.Model(&models.User{}).Clauses(clause.Locking{Strength: "UPDATE"}).Pluck("id", &ids).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Raw(`
+			SELECT * FROM (
+				SELECT u.id AS user_id, u.username, u.total_money AS stored, COALESCE(SUM(ROUND(
+					CASE tt.name WHEN ? THEN fr.amount WHEN ? THEN -fr.amount WHEN ? THEN fr.amount ELSE 0 END * cv.rate, 2
+				)), 0) AS computed
+				FROM users u
+				LEFT JOIN finance_records fr ON fr.user_id = u.id AND fr.deleted_at IS NULL
+				LEFT JOIN transaction_types tt ON tt.id = fr.transaction_type_id`+convertJoinTo("u.base_currency")+`
+				WHERE u.deleted_at IS NULL
+				GROUP BY u.id, u.username, u.total_money
+			) balances
+			WHERE stored <> computed
+			ORDER BY user_id`,
+			models.Income, models.Expense, models.Transfer, balanceTimezone,
+		).Scan(&drifts).Error; err != nil {
+			return err
+		}
+
+		if !fix {
+			return nil
+		}
+		for _, drift := range drifts {
+			if err := tx.Model(&models.User{}).Where("id = ?", drift.UserID).
+				UpdateColumn("total_money", drift.Computed).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return drifts, nil
+}
+
+// signedAmount returns the effect of record on its owner's balance:
+// positive for income, negative for expenses. Transfer halves are already
+// signed; records of the transfer type that are not part of a transfer are
+// refused, since they would change the balance without a counterpart.
+func signedAmount(tx *gorm.DB, record *models.FinanceRecord) (money.Amount, error) {
+	var transactionType models.TransactionType
+	if err := tx.First(&transactionType, record.TransactionTypeID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, ErrUnknownTransactionType
+		}
+		return 0, err
+	}
+
+	switch transactionType.Name {
+	case models.Income:
+		return record.Amount, nil
+	case models.Expense:
+		return record.Amount.Neg(), nil
+	case models.Transfer:
+		if record.TransferRecordID == nil {
+			return 0, ErrTransferRecord
+		}
+		return record.Amount, nil
+	}
+	return 0, ErrUnknownTransactionType
+}
+
+// adjustBalance moves the owner's balance by delta, an amount in the
+// currency of record, converted to the owner's base currency with the rate
+// of the record's day.
+func adjustBalance(tx *gorm.DB, record *models.FinanceRecord, delta money.Amount) error {
+	if delta.IsZero() {
+		return nil
+	}
+	return tx.Exec(`
+		UPDATE users u SET total_money = u.total_money + COALESCE((
+			SELECT ROUND(?::numeric * cv.rate, 2)
+			FROM (SELECT ?::text AS currency, ?::timestamptz AS created_at) fr`+convertJoinTo("u.base_currency")+`
+		), 0)
+		WHERE u.id = ?`,
+		delta, string(record.Currency), record.CreatedAt, balanceTimezone, record.UserID,
+	).Error
+}
+
+// recomputeBalance rebuilds the balance of one user, after their base
+// currency changed.
+func recomputeBalance(tx *gorm.DB, userID uint) error {
+	return tx.Exec(`
+		UPDATE users u SET total_money = COALESCE((
+			SELECT SUM(ROUND(
+				CASE tt.name WHEN ? THEN fr.amount WHEN ? THEN -fr.amount WHEN ? THEN fr.amount ELSE 0 END * cv.rate, 2
+			))
+			FROM finance_records fr
+			JOIN transaction_types tt ON tt.id = fr.transaction_type_id`+convertJoinTo("u.base_currency")+`
+			WHERE fr.user_id = u.id AND fr.deleted_at IS NULL
+		), 0)
+		WHERE u.id = ?`,
+		models.Income, models.Expense, models.Transfer, balanceTimezone, userID,
+	).Error
+}
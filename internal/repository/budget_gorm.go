@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"errors"
+	"go-finance-tracker/internal/models"
+	"go-finance-tracker/pkg/money"
+	"gorm.io/gorm"
+	"time"
+)
+
+const MaxBudgetStatusPeriods = 24
+
+var (
+	ErrBudgetNotFound = errors.New("budget not found")
+)
+
+type BudgetPeriodStatus struct {
+	Start       time.Time    `json:"start"`
+	End         time.Time    `json:"end"`
+	Budgeted    money.Amount `json:"budgeted"`
+	CarriedOver money.Amount `json:"carriedOver"`
+	Spent       money.Amount `json:"spent"`
+	Remaining   money.Amount `json:"remaining"`
+	PercentUsed float64      `json:"percentUsed"`
+	Overspent   bool         `json:"overspent"`
+}
+
+// BudgetStatus reports spending in Currency, the user's base currency, which
+// the budget amount is expressed in.
+type BudgetStatus struct {
+	Budget   models.Budget        `json:"budget"`
+	Currency money.Currency       `json:"currency"`
+	Periods  []BudgetPeriodStatus `json:"periods"`
+}
+
+type BudgetRepository struct {
+	db *gorm.DB
+}
+
+func NewBudgetRepository(db *gorm.DB) *BudgetRepository {
+	return &BudgetRepository{db: db}
+}
+
+func (r *BudgetRepository) GetAll(userID int) ([]models.Budget, error) {
+	var budgets []models.Budget
+	if err := r.db.Where("user_id = ?", userID).Order("id").Find(&budgets).Error; err != nil {
+		return nil, err
+	}
+	return budgets, nil
+}
+
+func (r *BudgetRepository) GetByID(userID int, id uint) (*models.Budget, error) {
+	var budget models.Budget
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&budget).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBudgetNotFound
+		}
+		return nil, err
+	}
+	return &budget, nil
+}
+
+func (r *BudgetRepository) Create(budget *models.Budget) error {
+	return r.db.Create(budget).Error
+}
+
+func (r *BudgetRepository) Update(budget *models.Budget) error {
+	result := r.db.Model(&models.Budget{}).
+		Where("id = ? AND user_id = ?", budget.ID, budget.UserID).
+		Select("CategoryID", "PeriodType", "Amount", "StartDate", "Rollover").
+		Updates(budget)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrBudgetNotFound
+	}
+	return nil
+}
+
+func (r *BudgetRepository) Delete(userID int, id uint) error {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.Budget{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrBudgetNotFound
+	}
+	return nil
+}
+
+// Status reports the last periods of the budget up to the one containing at,
+// newest first. Rollover budgets are replayed from their first period so the
+// carried amount is exact.
+func (r *BudgetRepository) Status(budget *models.Budget, at time.Time, periods int, loc *time.Location) (*BudgetStatus, error) {
+	currency, err := baseCurrency(r.db, budget.UserID)
+	if err != nil {
+		return nil, err
+	}
+	status := &BudgetStatus{Budget: *budget, Currency: currency, Periods: []BudgetPeriodStatus{}}
+
+	current := budget.PeriodIndexAt(at, loc)
+	if current < 0 {
+		return status, nil
+	}
+	if periods < 1 {
+		periods = 1
+	}
+	if periods > MaxBudgetStatusPeriods {
+		periods = MaxBudgetStatusPeriods
+	}
+
+	first := current - periods + 1
+	if budget.Rollover || first < 0 {
+		first = 0
+	}
+
+	starts := make([]time.Time, 0, current-first+1)
+	ends := make([]time.Time, 0, current-first+1)
+	for i := first; i <= current; i++ {
+		starts = append(starts, budget.PeriodStart(i, loc))
+		ends = append(ends, budget.PeriodStart(i+1, loc))
+	}
+
+	spent, err := r.spent(budget, starts, ends, currency, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	var carried money.Amount
+	all := make([]BudgetPeriodStatus, 0, len(starts))
+	for i := range starts {
+		period := BudgetPeriodStatus{
+			Start:       starts[i],
+			End:         ends[i],
+			CarriedOver: carried,
+			Budgeted:    budget.Amount.Add(carried),
+			Spent:       spent[i],
+		}
+		period.Remaining = period.Budgeted.Sub(period.Spent)
+		period.Overspent = period.Remaining.IsNegative()
+		if period.Budgeted.IsPositive() {
+			period.PercentUsed = float64(period.Spent.Minor()) * 100 / float64(period.Budgeted.Minor())
+		}
+		all = append(all, period)
+
+		carried = 0
+		if budget.Rollover && period.Remaining.IsPositive() {
+			carried = period.Remaining
+		}
+	}
+
+	if len(all) > periods {
+		all = all[len(all)-periods:]
+	}
+	for i := len(all) - 1; i >= 0; i-- {
+		status.Periods = append(status.Periods, all[i])
+	}
+	return status, nil
+}
+
+// spent sums the expenses of the budget category and its sub-categories for
+// every [starts[i], ends[i]) window in a single query, converted to currency
+// with the rate of each expense's day in loc. Income and transfers between
+// accounts are not spending and are ignored, as are expenses without a rate.
+// Split expenses count only their lines in the budget's categories.
+func (r *BudgetRepository) spent(budget *models.Budget, starts, ends []time.Time, currency money.Currency, loc *time.Location) ([]money.Amount, error) {
+	var rows []struct {
+		Idx   int
+		Spent money.Amount
+	}
+	if err := r.db.Raw(`
+		WITH RECURSIVE tree AS (
+			SELECT id FROM categories WHERE id = ?
+			UNION ALL
+			SELECT c.id FROM categories c JOIN tree t ON c.parent_id = t.id WHERE c.deleted_at IS NULL
+		)
+		SELECT w.idx, COALESCE(SUM(ROUND(COALESCE(fs.amount, fr.amount) * cv.rate, 2)), 0) AS spent
+		FROM unnest(ARRAY[?]::timestamptz[], ARRAY[?]::timestamptz[]) WITH ORDINALITY AS w(start_at, end_at, idx)
+		LEFT JOIN (finance_records fr LEFT JOIN finance_splits fs ON fs.finance_record_id = fr.id)
+			ON fr.user_id = ? AND fr.deleted_at IS NULL
+			AND fr.created_at >= w.start_at AND fr.created_at < w.end_at
+			AND COALESCE(fs.category_id, fr.category_id) IN (SELECT id FROM tree)
+			AND fr.transaction_type_id IN (SELECT id FROM transaction_types WHERE name = ?)`+convertJoin+`
+		GROUP BY w.idx
+		ORDER BY w.idx`,
+		append([]any{budget.CategoryID, starts, ends, budget.UserID, models.Expense},
+			convertArgs(currency, loc.String())...)...,
+	).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	spent := make([]money.Amount, len(starts))
+	for _, row := range rows {
+		// WITH ORDINALITY numbers from 1.
+		spent[row.Idx-1] = row.Spent
+	}
+	return spent, nil
+}
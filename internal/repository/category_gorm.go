@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"errors"
+	"go-finance-tracker/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrCategoryNotFound  = errors.New("category not found")
+	ErrCategoryReadOnly  = errors.New("system categories cannot be modified")
+	ErrCategoryExists    = errors.New("category with this name already exists")
+	ErrCategoryCycle     = errors.New("category cannot be its own ancestor")
+	ErrCategoryInUse     = errors.New("category still has finance records, recurring rules or budgets")
+	ErrCategoryMergeSelf = errors.New("category cannot be merged into itself")
+)
+
+type CategoryRepository struct {
+	db *gorm.DB
+}
+
+func NewCategoryRepository(db *gorm.DB) *CategoryRepository {
+	return &CategoryRepository{db: db}
+}
+
+// visibleTo limits a query to system categories and the ones owned by userID.
+func visibleTo(db *gorm.DB, userID int) *gorm.DB {
+	return db.Where("(user_id IS NULL OR user_id = ?)", userID)
+}
+
+func (r *CategoryRepository) GetAllVisible(userID int) ([]models.Category, error) {
+	var categories []models.Category
+	if err := visibleTo(r.db, userID).Order("parent_id NULLS FIRST").Order("name").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+// GetVisible returns the category if it is a system category or owned by
+// userID. Other users' categories are reported as missing.
+func (r *CategoryRepository) GetVisible(userID int, id uint) (*models.Category, error) {
+	return getVisibleCategory(r.db, userID, id)
+}
+
+func (r *CategoryRepository) Create(category *models.Category) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkCategoryPlacement(tx, category); err != nil {
+			return err
+		}
+		return tx.Create(category).Error
+	})
+}
+
+func (r *CategoryRepository) Update(category *models.Category) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if _, err := getOwnedCategory(tx, *category.UserID, category.ID); err != nil {
+			return err
+		}
+		if err := checkCategoryPlacement(tx, category); err != nil {
+			return err
+		}
+		if err := tx.Model(category).Select("Name", "ParentID").Updates(category).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.LedgerAccount{}).Where("category_id = ?", category.ID).
+			Update("name", category.Name).Error
+	})
+}
+
+// Delete removes a user-owned category. Categories that still have finance
+// records, recurring rules or budgets are refused; use Merge to move them
+// first.
+func (r *CategoryRepository) Delete(userID int, id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		category, err := getOwnedCategory(tx, uint(userID), id)
+		if err != nil {
+			return err
+		}
+
+		var postings int64
+		if err := tx.Model(&models.Posting{}).
+			Where("ledger_account_id IN (SELECT id FROM ledger_accounts WHERE category_id = ?)", id).
+			Count(&postings).Error; err != nil {
+			return err
+		}
+		var rules int64
+		if err := tx.Model(&models.RecurringRule{}).Where("category_id = ?", id).Count(&rules).Error; err != nil {
+			return err
+		}
+		var budgets int64
+		if err := tx.Model(&models.Budget{}).Where("category_id = ?", id).Count(&budgets).Error; err != nil {
+			return err
+		}
+		if postings > 0 || rules > 0 || budgets > 0 {
+			return ErrCategoryInUse
+		}
+
+		return deleteCategory(tx, category)
+	})
+}
+
+// Merge moves every ledger posting (and with them the finance records and
+// split lines), recurring rule and budget of the source category to the
+// target category and then deletes the source, all in one transaction.
+func (r *CategoryRepository) Merge(userID int, sourceID, targetID uint) error {
+	if sourceID == targetID {
+		return ErrCategoryMergeSelf
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		source, err := getOwnedCategory(tx, uint(userID), sourceID)
+		if err != nil {
+			return err
+		}
+		if _, err := getVisibleCategory(tx, userID, targetID); err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.RecurringRule{}).Where("category_id = ?", sourceID).
+			Update("category_id", targetID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Budget{}).Where("category_id = ?", sourceID).
+			Update("category_id", targetID).Error; err != nil {
+			return err
+		}
+		if err := moveCategoryLedger(tx, sourceID, targetID); err != nil {
+			return err
+		}
+
+		return deleteCategory(tx, source)
+	})
+}
+
+func getVisibleCategory(tx *gorm.DB, userID int, id uint) (*models.Category, error) {
+	var category models.Category
+	if err := visibleTo(tx, userID).Where("id = ?", id).First(&category).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, err
+	}
+	return &category, nil
+}
+
+// getOwnedCategory locks and returns a category the user may modify.
+func getOwnedCategory(tx *gorm.DB, userID uint, id uint) (*models.Category, error) {
+	category, err := getVisibleCategory(tx.Clauses(clause.Locking{Strength: "UPDATE"}), int(userID), id)
+	if err != nil {
+		return nil, err
+	}
+	if category.IsSystem() {
+		return nil, ErrCategoryReadOnly
+	}
+	return category, nil
+}
+
+// deleteCategory re-attaches the children of category to its own parent so
+// that the hierarchy stays connected, then deletes it.
+func deleteCategory(tx *gorm.DB, category *models.Category) error {
+	if err := tx.Model(&models.Category{}).Where("parent_id = ?", category.ID).
+		Update("parent_id", category.ParentID).Error; err != nil {
+		return err
+	}
+	return tx.Delete(category).Error
+}
+
+// checkCategoryPlacement validates the parent of category and that its name
+// is unique among the siblings visible to the owner.
+func checkCategoryPlacement(tx *gorm.DB, category *models.Category) error {
+	userID := int(*category.UserID)
+
+	if category.ParentID != nil {
+		if *category.ParentID == category.ID {
+			return ErrCategoryCycle
+		}
+		if _, err := getVisibleCategory(tx, userID, *category.ParentID); err != nil {
+			return err
+		}
+
+		if category.ID != 0 {
+			// Walk up from the new parent; reaching the category itself
+			// would close a cycle.
+			current := category.ParentID
+			for current != nil {
+				if *current == category.ID {
+					return ErrCategoryCycle
+				}
+				var parent models.Category
+				if err := tx.Select("id", "parent_id").First(&parent, *current).Error; err != nil {
+					return err
+				}
+				current = parent.ParentID
+			}
+		}
+	}
+
+	siblings := visibleTo(tx.Model(&models.Category{}), userID).
+		Where("lower(name) = lower(?) AND id <> ?", category.Name, category.ID)
+	if category.ParentID == nil {
+		siblings = siblings.Where("parent_id IS NULL")
+	} else {
+		siblings = siblings.Where("parent_id = ?", *category.ParentID)
+	}
+	var count int64
+	if err := siblings.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrCategoryExists
+	}
+	return nil
+}
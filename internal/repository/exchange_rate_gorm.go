@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"errors"
+	"go-finance-tracker/internal/models"
+	"go-finance-tracker/pkg/money"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+var (
+	ErrSameCurrencyRate = errors.New("exchange rate must be between two different currencies")
+)
+
+// ExchangeRateQuery filters stored rates, newest first. Zero-valued filters
+// are ignored.
+type ExchangeRateQuery struct {
+	From  money.Currency
+	To    money.Currency
+	Since time.Time
+	Until time.Time
+	Limit int
+}
+
+type ExchangeRateRepository struct {
+	db *gorm.DB
+}
+
+func NewExchangeRateRepository(db *gorm.DB) *ExchangeRateRepository {
+	return &ExchangeRateRepository{db: db}
+}
+
+func (r *ExchangeRateRepository) Find(query ExchangeRateQuery) ([]models.ExchangeRate, error) {
+	if query.Limit <= 0 {
+		query.Limit = DefaultUserPageSize
+	}
+	if query.Limit > MaxUserPageSize {
+		query.Limit = MaxUserPageSize
+	}
+
+	db := r.db.Model(&models.ExchangeRate{})
+	if query.From != "" {
+		db = db.Where("from_currency = ?", query.From)
+	}
+	if query.To != "" {
+		db = db.Where("to_currency = ?", query.To)
+	}
+	if !query.Since.IsZero() {
+		db = db.Where("date >= ?", query.Since)
+	}
+	if !query.Until.IsZero() {
+		db = db.Where("date <= ?", query.Until)
+	}
+
+	rates := []models.ExchangeRate{}
+	err := db.Order("date DESC, from_currency, to_currency").Limit(query.Limit).Find(&rates).Error
+	return rates, err
+}
+
+// Upsert stores the rates in one transaction. A rate for a pair and day that
+// is already known replaces the stored one, so imports can be repeated; when
+// the input itself repeats a pair and day, its last rate wins.
+func (r *ExchangeRateRepository) Upsert(rates []models.ExchangeRate) error {
+	for _, rate := range rates {
+		if rate.FromCurrency == rate.ToCurrency {
+			return ErrSameCurrencyRate
+		}
+	}
+	rates = latestRates(rates)
+	if len(rates) == 0 {
+		return nil
+	}
+
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "from_currency"}, {Name: "to_currency"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "updated_at"}),
+	}).CreateInBatches(rates, 500).Error
+}
+
+// latestRates drops every rate that a later one for the same pair and day
+// overrides. Postgres refuses to update a row twice in one INSERT ... ON
+// CONFLICT statement.
+func latestRates(rates []models.ExchangeRate) []models.ExchangeRate {
+	type key struct {
+		from, to money.Currency
+		date     string
+	}
+	index := make(map[key]int, len(rates))
+	latest := make([]models.ExchangeRate, 0, len(rates))
+	for _, rate := range rates {
+		k := key{rate.FromCurrency, rate.ToCurrency, rate.Date.Format("2006-01-02")}
+		if i, ok := index[k]; ok {
+			latest[i] = rate
+			continue
+		}
+		index[k] = len(latest)
+		latest = append(latest, rate)
+	}
+	return latest
+}
+
+// convertJoin is a lateral join that exposes cv.rate, the rate from the
+// currency of the finance record fr to another currency. It uses the latest
+// rate on or before the record's local day, or the inverse of the opposite
+// pair when only that one is known, and is NULL when there is no rate at all.
+// Callers convert with ROUND(amount * cv.rate, 2), which also works for the
+// split lines of fr. Its arguments come from convertArgs.
+var convertJoin = convertJoinTo("?::text")
+
+func convertArgs(currency money.Currency, timezone string) []any {
+	return []any{string(currency), string(currency), string(currency), timezone}
+}
+
+// convertJoinTo builds convertJoin for a target currency given as an SQL
+// expression, e.g. a column. Its only argument is the timezone.
+func convertJoinTo(currency string) string {
+	return `
+	LEFT JOIN LATERAL (
+		SELECT CASE WHEN fr.currency = ` + currency + ` THEN 1 ELSE (
+			SELECT CASE WHEN er.from_currency = fr.currency THEN er.rate ELSE 1 / er.rate END
+			FROM exchange_rates er
+			WHERE ((er.from_currency = fr.currency AND er.to_currency = ` + currency + `)
+					OR (er.from_currency = ` + currency + ` AND er.to_currency = fr.currency))
+				AND er.date <= (fr.created_at AT TIME ZONE ?::text)::date
+			ORDER BY er.date DESC, er.from_currency = fr.currency DESC
+			LIMIT 1
+		) END AS rate
+	) cv ON true`
+}
+
+// baseCurrency returns the currency the user's reports and budgets are in.
+func baseCurrency(db *gorm.DB, userID uint) (money.Currency, error) {
+	var user models.User
+	if err := db.Select("base_currency").First(&user, userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", ErrUserNotFound
+		}
+		return "", err
+	}
+	return user.BaseCurrency, nil
+}
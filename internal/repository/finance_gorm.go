@@ -1,14 +1,16 @@
 package repository
 
 import (
-	"database/sql"
 	"errors"
 	"go-finance-tracker/internal/models"
+	"go-finance-tracker/pkg/money"
 	"gorm.io/gorm"
 )
 
 var (
-	ErrFinanceHistoryNotFound = errors.New("finance history not found")
+	ErrFinanceRecordNotFound = errors.New("finance record not found")
+	ErrInvalidSplits         = errors.New("a split needs at least two non-zero lines that sum to the record amount")
+	ErrSplitCategory         = errors.New("a split record is listed under the category of its first line")
 )
 
 type UserFinanceRepository struct {
@@ -19,20 +21,180 @@ func NewUserFinanceRepository(db *gorm.DB) *UserFinanceRepository {
 	return &UserFinanceRepository{db: db}
 }
 
-func (r *UserFinanceRepository) GetAll(userID int) (*[]models.FinanceRecord, error) {
-	var records []models.FinanceRecord
-	if err := r.db.Where("user_id = ?", userID).Preload("TransactionType").Preload("Category").Find(&records).Error; err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, ErrFinanceHistoryNotFound
+// GetByID returns the record only when it belongs to userID, so that other
+// users' records are indistinguishable from missing ones.
+func (r *UserFinanceRepository) GetByID(userID int, id uint) (*models.FinanceRecord, error) {
+	var record models.FinanceRecord
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).
+		Preload("TransactionType").Preload("Category").Preload("Splits", orderByID).First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFinanceRecordNotFound
 		}
 		return nil, err
 	}
-	return &records, nil
+	return &record, nil
 }
 
+// Create books the record as a journal entry and applies it to the owner's
+// balance in the same transaction. On return record is read back from the
+// journal, with its ID.
 func (r *UserFinanceRepository) Create(record *models.FinanceRecord) error {
-	if err := r.db.Create(record).Error; err != nil {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		_, err := createFinanceRecord(tx, record)
 		return err
+	})
+}
+
+// Update replaces the editable fields and the split lines of the record by
+// re-booking its journal entry, and moves the owner's balance by the
+// difference between the old and the new effect. The record keeps its ID,
+// date and recurring occurrence. Transfer halves cannot be edited, only
+// deleted.
+func (r *UserFinanceRepository) Update(record *models.FinanceRecord) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		old, err := lockFinanceRecord(tx, int(record.UserID), record.ID)
+		if err != nil {
+			return err
+		}
+		if old.TransferRecordID != nil {
+			return ErrTransferRecord
+		}
+
+		oldDelta, err := signedAmount(tx, old)
+		if err != nil {
+			return err
+		}
+		newDelta, err := signedAmount(tx, record)
+		if err != nil {
+			return err
+		}
+		if record.Currency, err = accountCurrency(tx, record.AccountID); err != nil {
+			return err
+		}
+		if err := checkSplits(record); err != nil {
+			return err
+		}
+
+		record.CreatedAt = old.CreatedAt
+		record.RecurringRuleID = old.RecurringRuleID
+		record.Occurrence = old.Occurrence
+		entry, err := financeEntry(tx, record)
+		if err != nil {
+			return err
+		}
+		entry.ID = old.JournalEntryID
+		entry.Postings[0].ID = old.ID
+		if err := replaceJournalEntry(tx, entry); err != nil {
+			return err
+		}
+
+		updated, err := getFinanceRecord(tx, old.ID)
+		if err != nil {
+			return err
+		}
+		if err := adjustBalance(tx, old, oldDelta.Neg()); err != nil {
+			return err
+		}
+		return adjustBalance(tx, updated, newDelta)
+	})
+}
+
+// Delete removes the journal entry of the record and reverts its effect on
+// the owner's balance. Both halves of a transfer share an entry, so deleting
+// either deletes the whole transfer.
+func (r *UserFinanceRepository) Delete(userID int, id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		old, err := lockFinanceRecord(tx, userID, id)
+		if err != nil {
+			return err
+		}
+		records, err := loadFinanceRecords(tx, old.JournalEntryID)
+		if err != nil {
+			return err
+		}
+
+		for i := range records {
+			delta, err := signedAmount(tx, &records[i])
+			if err != nil {
+				return err
+			}
+			if err := adjustBalance(tx, &records[i], delta.Neg()); err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&models.JournalEntry{}, old.JournalEntryID).Error
+	})
+}
+
+// createFinanceRecord books the record in the currency of its account and
+// applies it to the owner's balance. Occurrences of a recurring rule that
+// were already materialized are skipped and reported as not created.
+func createFinanceRecord(tx *gorm.DB, record *models.FinanceRecord) (bool, error) {
+	delta, err := signedAmount(tx, record)
+	if err != nil {
+		return false, err
+	}
+	if record.Currency, err = accountCurrency(tx, record.AccountID); err != nil {
+		return false, err
+	}
+	if err := checkSplits(record); err != nil {
+		return false, err
+	}
+
+	if record.RecurringRuleID != nil {
+		var existing int64
+		if err := tx.Model(&models.JournalEntry{}).
+			Where("recurring_rule_id = ? AND occurrence = ?", *record.RecurringRuleID, *record.Occurrence).
+			Count(&existing).Error; err != nil {
+			return false, err
+		}
+		if existing > 0 {
+			return false, nil
+		}
+	}
+
+	entry, err := financeEntry(tx, record)
+	if err != nil {
+		return false, err
+	}
+	if err := createJournalEntry(tx, entry); err != nil {
+		return false, err
+	}
+	created, err := getFinanceRecord(tx, entry.Postings[0].ID)
+	if err != nil {
+		return false, err
+	}
+	*record = *created
+	return true, adjustBalance(tx, record, delta)
+}
+
+// checkSplits verifies that the split lines of record, if any, add up to
+// its amount and that its category is the one of the first line.
+func checkSplits(record *models.FinanceRecord) error {
+	if len(record.Splits) == 0 {
+		return nil
+	}
+	if len(record.Splits) == 1 {
+		return ErrInvalidSplits
+	}
+	if record.CategoryID != record.Splits[0].CategoryID {
+		return ErrSplitCategory
+	}
+
+	var sum money.Amount
+	for _, split := range record.Splits {
+		if split.Amount.IsZero() {
+			return ErrInvalidSplits
+		}
+		sum = sum.Add(split.Amount)
+	}
+	if sum != record.Amount {
+		return ErrInvalidSplits
 	}
 	return nil
 }
+
+// orderByID keeps preloaded split lines in the order they were entered.
+func orderByID(db *gorm.DB) *gorm.DB {
+	return db.Order("id")
+}
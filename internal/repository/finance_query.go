@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"go-finance-tracker/internal/models"
+	"go-finance-tracker/pkg/money"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	DefaultFinancePageSize = 20
+	MaxFinancePageSize     = 100
+)
+
+var (
+	ErrInvalidCursor    = errors.New("invalid cursor")
+	ErrInvalidSortField = errors.New("invalid sort field")
+)
+
+type FinanceSortField string
+
+const (
+	SortByDate   FinanceSortField = "date"
+	SortByAmount FinanceSortField = "amount"
+)
+
+type SortDirection string
+
+const (
+	SortAsc  SortDirection = "asc"
+	SortDesc SortDirection = "desc"
+)
+
+// financeSortColumns maps the public sort fields onto finance_records columns.
+// Only whitelisted columns ever reach the ORDER BY clause.
+var financeSortColumns = map[FinanceSortField]string{
+	SortByDate:   "created_at",
+	SortByAmount: "amount",
+}
+
+// FinanceQuery describes a filtered, sorted and paginated view of a user's
+// finance history. Zero-valued filters are ignored. CategoryID also matches
+// records with a split line in the category.
+type FinanceQuery struct {
+	UserID            int
+	From              *time.Time
+	To                *time.Time
+	AccountID         *uint
+	CategoryID        *uint
+	TransactionTypeID *uint
+	MinAmount         *money.Amount
+	MaxAmount         *money.Amount
+	NoteContains      string
+	SortBy            FinanceSortField
+	SortDir           SortDirection
+	Cursor            string
+	Limit             int
+}
+
+type FinancePage struct {
+	Records    []models.FinanceRecord `json:"records"`
+	NextCursor string                 `json:"nextCursor,omitempty"`
+	Total      int64                  `json:"total"`
+}
+
+// financeCursor points at the last record of a page: the value of the sort
+// column and the record ID used as a tie-breaker.
+type financeCursor struct {
+	Value string `json:"v"`
+	ID    uint   `json:"id"`
+}
+
+func (q *FinanceQuery) normalize() error {
+	if q.SortBy == "" {
+		q.SortBy = SortByDate
+	}
+	if _, ok := financeSortColumns[q.SortBy]; !ok {
+		return ErrInvalidSortField
+	}
+	if q.SortDir != SortAsc {
+		q.SortDir = SortDesc
+	}
+	if q.Limit <= 0 {
+		q.Limit = DefaultFinancePageSize
+	}
+	if q.Limit > MaxFinancePageSize {
+		q.Limit = MaxFinancePageSize
+	}
+	return nil
+}
+
+func (q *FinanceQuery) apply(db *gorm.DB) *gorm.DB {
+	db = db.Where("user_id = ?", q.UserID)
+	if q.From != nil {
+		db = db.Where("created_at >= ?", *q.From)
+	}
+	if q.To != nil {
+		db = db.Where("created_at < ?", *q.To)
+	}
+	if q.AccountID != nil {
+		db = db.Where("account_id = ?", *q.AccountID)
+	}
+	if q.CategoryID != nil {
+		db = db.Where("(category_id = ? OR id IN (SELECT finance_record_id FROM finance_splits WHERE category_id = ?))",
+			*q.CategoryID, *q.CategoryID)
+	}
+	if q.TransactionTypeID != nil {
+		db = db.Where("transaction_type_id = ?", *q.TransactionTypeID)
+	}
+	if q.MinAmount != nil {
+		db = db.Where("amount >= ?", *q.MinAmount)
+	}
+	if q.MaxAmount != nil {
+		db = db.Where("amount <= ?", *q.MaxAmount)
+	}
+	if q.NoteContains != "" {
+		db = db.Where("note ILIKE ?", "%"+escapeLike(q.NoteContains)+"%")
+	}
+	return db
+}
+
+func (q *FinanceQuery) applyCursor(db *gorm.DB) (*gorm.DB, error) {
+	if q.Cursor == "" {
+		return db, nil
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(q.Cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var cursor financeCursor
+	if err := json.Unmarshal(raw, &cursor); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var value any
+	switch q.SortBy {
+	case SortByDate:
+		value, err = time.Parse(time.RFC3339Nano, cursor.Value)
+	case SortByAmount:
+		value, err = money.Parse(cursor.Value)
+	}
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	column := financeSortColumns[q.SortBy]
+	op := "<"
+	if q.SortDir == SortAsc {
+		op = ">"
+	}
+	return db.Where("("+column+" "+op+" ? OR ("+column+" = ? AND id "+op+" ?))", value, value, cursor.ID), nil
+}
+
+func (q *FinanceQuery) nextCursor(last models.FinanceRecord) string {
+	var value string
+	switch q.SortBy {
+	case SortByDate:
+		value = last.CreatedAt.UTC().Format(time.RFC3339Nano)
+	case SortByAmount:
+		value = last.Amount.String()
+	}
+
+	raw, _ := json.Marshal(financeCursor{Value: value, ID: last.ID})
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func (r *UserFinanceRepository) Find(query FinanceQuery) (*FinancePage, error) {
+	if err := query.normalize(); err != nil {
+		return nil, err
+	}
+
+	var total int64
+	if err := query.apply(r.db.Model(&models.FinanceRecord{})).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	db, err := query.applyCursor(query.apply(r.db.Model(&models.FinanceRecord{})))
+	if err != nil {
+		return nil, err
+	}
+
+	column := financeSortColumns[query.SortBy]
+	direction := string(query.SortDir)
+	var records []models.FinanceRecord
+	if err := db.Order(column+" "+direction).Order("id "+direction).
+		Limit(query.Limit+1).
+		Preload("TransactionType").Preload("Category").Preload("Splits", orderByID).
+		Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	page := &FinancePage{Records: records, Total: total}
+	if len(records) > query.Limit {
+		page.Records = records[:query.Limit]
+		page.NextCursor = query.nextCursor(page.Records[query.Limit-1])
+	}
+	return page, nil
+}
+
+func escapeLike(s string) string {
+	escaped := make([]rune, 0, len(s))
+	for _, c := range s {
+		if c == '%' || c == '_' || c == '\\' {
+			escaped = append(escaped, '\\')
+		}
+		escaped = append(escaped, c)
+	}
+	return string(escaped)
+}
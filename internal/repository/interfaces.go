@@ -1,22 +1,124 @@
 package repository
 
-import "go-finance-tracker/internal/models"
+import (
+	"go-finance-tracker/internal/ledger"
+	"go-finance-tracker/internal/models"
+	"time"
+)
 
 type (
 	UserRepo interface {
 		GetUserByID(id uint) (*models.User, error)
 		GetUserByUsername(username string) (*models.User, error)
+		GetUserByEmail(email string) (*models.User, error)
+		GetUserIncludingDeleted(id uint) (*models.User, error)
 		GetAllUsers() ([]models.User, error)
-		DeleteUser(id uint) error
+		SearchUsers(query UserQuery) (*UserPage, error)
+		DeleteUser(id uint, entry *models.AuditLog) error
+		RestoreUser(id uint, entry *models.AuditLog) error
 		CreateUser(user *models.User) error
-		//UpdateUser(id uint, updateForm forms.UpdateForm) error
+		UpdateUser(user *models.User, entry *models.AuditLog) error
+		SetLocked(id uint, locked bool, entry *models.AuditLog) error
+		ResetCredentials(id uint, passwordHash string, entry *models.AuditLog) error
+		ChangePassword(id uint, passwordHash string, keepSessionID uint) error
 	}
 	RoleRepo interface {
 		GetByID(id uint) (*models.Role, error)
 		GetByName(name string) (*models.Role, error)
+		GetPermissionNames(roleIDs []uint) ([]string, error)
+		Assign(userID uint, role *models.Role, entry *models.AuditLog) error
+		Revoke(userID uint, role *models.Role, entry *models.AuditLog) error
+	}
+	LoginAttemptRepo interface {
+		CountFailuresFromIP(ip string, since time.Time) (int64, error)
+		RecordFailure(attempt *models.LoginAttempt, maxFailures int, lockFor time.Duration) (*time.Time, error)
+		RecordSuccess(attempt *models.LoginAttempt) error
+	}
+	AuditRepo interface {
+		Find(query AuditQuery) (*AuditPage, error)
+	}
+	MFARepo interface {
+		SetPendingSecret(userID uint, secret string) error
+		Enable(userID uint, step int64, codeHashes []string) error
+		Disable(userID uint, entry *models.AuditLog) error
+		UseStep(userID uint, step int64) (bool, error)
+		UseRecoveryCode(userID uint, codeHash string) (bool, error)
+		ReplaceRecoveryCodes(userID uint, codeHashes []string) error
+	}
+	UserTokenRepo interface {
+		Issue(token *models.UserToken) error
+		ResetPassword(tokenHash, passwordHash string) (uint, error)
+		VerifyEmail(tokenHash string) (uint, error)
+		LastIssuedAt(userID uint, purpose models.TokenPurpose) (*time.Time, error)
+		GetTokenUser(purpose models.TokenPurpose, tokenHash string) (*models.User, error)
+	}
+	SessionRepo interface {
+		Create(session *models.Session, tokenHash string) error
+		Rotate(tokenHash, newTokenHash, ip, userAgent string, expiresAt time.Time) (*models.Session, error)
+		GetByID(id uint) (*models.Session, error)
+		GetActive(userID int) ([]models.Session, error)
+		Revoke(userID int, id uint) error
+	}
+	AccountRepo interface {
+		GetAll(userID int, includeArchived bool) ([]models.Account, error)
+		GetByID(userID int, id uint) (*models.Account, error)
+		Resolve(userID int, id uint) (*models.Account, error)
+		Create(account *models.Account) error
+		Update(account *models.Account) error
+		Delete(userID int, id uint) error
 	}
 	FinanceRepo interface {
-		GetAll(userID int) (*[]models.FinanceRecord, error)
+		Find(query FinanceQuery) (*FinancePage, error)
+		GetByID(userID int, id uint) (*models.FinanceRecord, error)
 		Create(record *models.FinanceRecord) error
+		Update(record *models.FinanceRecord) error
+		Delete(userID int, id uint) error
+	}
+	LedgerRepo interface {
+		GetAccounts(userID int) ([]models.LedgerAccount, error)
+		CreateAccount(account *models.LedgerAccount) error
+		FindEntries(query JournalQuery) (*JournalPage, error)
+		GetEntry(userID int, id uint) (*models.JournalEntry, error)
+		CreateEntry(entry *models.JournalEntry) error
+		DeleteEntry(userID int, id uint) error
+		TrialBalance(userID int, at time.Time) (*ledger.TrialBalance, error)
+		BalanceSheet(userID int, at time.Time) (*ledger.BalanceSheet, error)
+	}
+	TransferRepo interface {
+		Create(transfer NewTransfer) (*Transfer, error)
+	}
+	ExchangeRateRepo interface {
+		Find(query ExchangeRateQuery) ([]models.ExchangeRate, error)
+		Upsert(rates []models.ExchangeRate) error
+	}
+	CategoryRepo interface {
+		GetAllVisible(userID int) ([]models.Category, error)
+		GetVisible(userID int, id uint) (*models.Category, error)
+		Create(category *models.Category) error
+		Update(category *models.Category) error
+		Delete(userID int, id uint) error
+		Merge(userID int, sourceID, targetID uint) error
+	}
+	BudgetRepo interface {
+		GetAll(userID int) ([]models.Budget, error)
+		GetByID(userID int, id uint) (*models.Budget, error)
+		Create(budget *models.Budget) error
+		Update(budget *models.Budget) error
+		Delete(userID int, id uint) error
+		Status(budget *models.Budget, at time.Time, periods int, loc *time.Location) (*BudgetStatus, error)
+	}
+	RecurringRepo interface {
+		GetAll(userID int) ([]models.RecurringRule, error)
+		GetByID(userID int, id uint) (*models.RecurringRule, error)
+		Create(rule *models.RecurringRule) error
+		Update(rule *models.RecurringRule) error
+		Delete(userID int, id uint) error
+		MaterializeDue(now time.Time, batch int) (claimed, created int, err error)
+	}
+	ReportRepo interface {
+		Summary(query SummaryQuery) (*Summary, error)
+	}
+	BalanceRepo interface {
+		Recompute(fix bool) ([]BalanceDrift, error)
 	}
 )
@@ -0,0 +1,240 @@
+package repository
+
+import (
+	"errors"
+	"go-finance-tracker/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+var (
+	ErrZeroAmount = errors.New("amount must not be zero")
+)
+
+// The journal is the store of income, expenses and transfers; the
+// finance_records and finance_splits views present its entries to the
+// finance API. The helpers below translate between the two.
+
+// financeEntry builds the journal entry of an income or expense record: its
+// account on one side, the income or expense ledger account of its
+// category, or of the category of each split line, on the other. The
+// posting to the account comes first and becomes the record's ID.
+func financeEntry(tx *gorm.DB, record *models.FinanceRecord) (*models.JournalEntry, error) {
+	var transactionType models.TransactionType
+	if err := tx.First(&transactionType, record.TransactionTypeID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUnknownTransactionType
+		}
+		return nil, err
+	}
+
+	delta := record.Amount
+	categoryType := models.LedgerIncome
+	switch transactionType.Name {
+	case models.Income:
+	case models.Expense:
+		delta = delta.Neg()
+		categoryType = models.LedgerExpense
+	case models.Transfer:
+		return nil, ErrTransferRecord
+	default:
+		return nil, ErrUnknownTransactionType
+	}
+	if delta.IsZero() {
+		return nil, ErrZeroAmount
+	}
+
+	account, err := accountLedgerAccount(tx, record.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	postings := []models.Posting{{LedgerAccountID: account.ID, Amount: delta, Currency: record.Currency}}
+
+	lines := record.Splits
+	if len(lines) == 0 {
+		lines = []models.FinanceSplit{{CategoryID: record.CategoryID, Amount: record.Amount}}
+	}
+	for _, line := range lines {
+		category, err := categoryLedgerAccount(tx, record.UserID, line.CategoryID, categoryType)
+		if err != nil {
+			return nil, err
+		}
+		amount := line.Amount.Neg()
+		if categoryType == models.LedgerExpense {
+			amount = line.Amount
+		}
+		postings = append(postings, models.Posting{
+			LedgerAccountID: category.ID,
+			Amount:          amount,
+			Currency:        record.Currency,
+			Note:            line.Note,
+		})
+	}
+
+	date := record.CreatedAt
+	if date.IsZero() {
+		date = time.Now()
+	}
+	return &models.JournalEntry{
+		UserID:          record.UserID,
+		Date:            date,
+		Description:     record.Note,
+		RecurringRuleID: record.RecurringRuleID,
+		Occurrence:      record.Occurrence,
+		Postings:        postings,
+	}, nil
+}
+
+// getFinanceRecord reads a record with its split lines from the journal.
+func getFinanceRecord(tx *gorm.DB, id uint) (*models.FinanceRecord, error) {
+	var record models.FinanceRecord
+	if err := tx.Preload("Splits", orderByID).First(&record, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFinanceRecordNotFound
+		}
+		return nil, err
+	}
+	return &record, nil
+}
+
+// loadFinanceRecords reads the records of a journal entry: one for an income
+// or expense, both halves for a transfer.
+func loadFinanceRecords(tx *gorm.DB, entryID uint) ([]models.FinanceRecord, error) {
+	var records []models.FinanceRecord
+	err := tx.Where("journal_entry_id = ?", entryID).Preload("Splits", orderByID).Order("id").Find(&records).Error
+	return records, err
+}
+
+// lockFinanceRecord locks the journal entry of the user's record and reads
+// the record again, so that it cannot change until the transaction ends.
+func lockFinanceRecord(tx *gorm.DB, userID int, id uint) (*models.FinanceRecord, error) {
+	var record models.FinanceRecord
+	if err := tx.Select("journal_entry_id").Where("id = ? AND user_id = ?", id, userID).
+		First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFinanceRecordNotFound
+		}
+		return nil, err
+	}
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").
+		First(&models.JournalEntry{}, record.JournalEntryID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrFinanceRecordNotFound
+		}
+		return nil, err
+	}
+	return getFinanceRecord(tx, id)
+}
+
+// syncAccountLedger keeps the ledger account of account in step with its
+// name and type, and re-books its opening balance against the opening
+// balances equity account.
+func syncAccountLedger(tx *gorm.DB, account *models.Account) error {
+	ledgerAccount := models.LedgerAccount{UserID: account.UserID, AccountID: &account.ID}
+	if err := tx.Where("account_id = ?", account.ID).
+		Assign(models.LedgerAccount{Name: account.Name, Type: account.Type.LedgerType()}).
+		FirstOrCreate(&ledgerAccount).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Where("opening_account_id = ?", account.ID).Delete(&models.JournalEntry{}).Error; err != nil {
+		return err
+	}
+	if account.OpeningBalance.IsZero() {
+		return nil
+	}
+
+	equity := models.LedgerAccount{
+		UserID: account.UserID,
+		Name:   models.OpeningBalancesName,
+		Type:   models.LedgerEquity,
+	}
+	if err := tx.Where("user_id = ? AND name = ? AND account_id IS NULL AND category_id IS NULL",
+		account.UserID, models.OpeningBalancesName).FirstOrCreate(&equity).Error; err != nil {
+		return err
+	}
+
+	return createJournalEntry(tx, &models.JournalEntry{
+		UserID:           account.UserID,
+		Date:             account.CreatedAt,
+		Description:      models.OpeningBalancesName,
+		OpeningAccountID: &account.ID,
+		Postings: []models.Posting{
+			{LedgerAccountID: ledgerAccount.ID, Amount: account.OpeningBalance, Currency: account.Currency},
+			{LedgerAccountID: equity.ID, Amount: account.OpeningBalance.Neg(), Currency: account.Currency},
+		},
+	})
+}
+
+// unlinkAccountLedger removes the opening balance and the ledger account of
+// an account that is deleted, which only happens once it has no records.
+func unlinkAccountLedger(tx *gorm.DB, accountID uint) error {
+	if err := tx.Where("opening_account_id = ?", accountID).Delete(&models.JournalEntry{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("account_id = ?", accountID).Delete(&models.LedgerAccount{}).Error
+}
+
+// moveCategoryLedger re-points the postings of the ledger accounts of the
+// source category to those of the target, for a category merge.
+func moveCategoryLedger(tx *gorm.DB, sourceID, targetID uint) error {
+	var sources []models.LedgerAccount
+	if err := tx.Where("category_id = ?", sourceID).Find(&sources).Error; err != nil {
+		return err
+	}
+
+	for _, source := range sources {
+		target, err := categoryLedgerAccount(tx, source.UserID, targetID, source.Type)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Posting{}).Where("ledger_account_id = ?", source.ID).
+			Update("ledger_account_id", target.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&source).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func accountLedgerAccount(tx *gorm.DB, accountID uint) (*models.LedgerAccount, error) {
+	var ledgerAccount models.LedgerAccount
+	if err := tx.Where("account_id = ?", accountID).First(&ledgerAccount).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLedgerAccountNotFound
+		}
+		return nil, err
+	}
+	return &ledgerAccount, nil
+}
+
+// categoryLedgerAccount returns the user's ledger account of the category
+// and type, opening it on first use. Categories may hold both income and
+// expenses, so there can be one of each.
+func categoryLedgerAccount(tx *gorm.DB, userID, categoryID uint, accountType models.LedgerAccountType) (*models.LedgerAccount, error) {
+	ledgerAccount := models.LedgerAccount{UserID: userID, Type: accountType, CategoryID: &categoryID}
+	err := tx.Where("user_id = ? AND category_id = ? AND type = ?", userID, categoryID, accountType).
+		First(&ledgerAccount).Error
+	if err == nil {
+		return &ledgerAccount, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	var category models.Category
+	if err := tx.Unscoped().Select("name").First(&category, categoryID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, err
+	}
+	ledgerAccount.Name = category.Name
+	if err := tx.Create(&ledgerAccount).Error; err != nil {
+		return nil, err
+	}
+	return &ledgerAccount, nil
+}
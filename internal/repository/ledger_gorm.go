@@ -0,0 +1,287 @@
+package repository
+
+import (
+	"errors"
+	"go-finance-tracker/internal/ledger"
+	"go-finance-tracker/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+var (
+	ErrLedgerAccountNotFound = errors.New("ledger account not found")
+	ErrLedgerAccountExists   = errors.New("a ledger account with this name already exists")
+	ErrLedgerAccountType     = errors.New("invalid ledger account type")
+	ErrLedgerAccountLinked   = errors.New("ledger accounts of accounts and categories are posted to through /v1/finance")
+	ErrJournalEntryNotFound  = errors.New("journal entry not found")
+	ErrJournalEntryManaged   = errors.New("journal entries of finance records and opening balances change with them")
+)
+
+// JournalQuery selects the entries of UserID, newest first, optionally only
+// those posting to LedgerAccountID.
+type JournalQuery struct {
+	UserID          int
+	LedgerAccountID uint
+	Cursor          string
+	Limit           int
+}
+
+type JournalPage struct {
+	Entries    []models.JournalEntry `json:"entries"`
+	NextCursor string                `json:"nextCursor,omitempty"`
+}
+
+type LedgerRepository struct {
+	db *gorm.DB
+}
+
+func NewLedgerRepository(db *gorm.DB) *LedgerRepository {
+	return &LedgerRepository{db: db}
+}
+
+func (r *LedgerRepository) GetAccounts(userID int) ([]models.LedgerAccount, error) {
+	var accounts []models.LedgerAccount
+	err := r.db.Where("user_id = ?", userID).Order("type, name").Find(&accounts).Error
+	return accounts, err
+}
+
+// CreateAccount opens a ledger account that is linked to no account or
+// category. The name of the opening balances account is reserved.
+func (r *LedgerRepository) CreateAccount(account *models.LedgerAccount) error {
+	if !account.Type.Valid() {
+		return ErrLedgerAccountType
+	}
+	account.AccountID = nil
+	account.CategoryID = nil
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.LedgerAccount{}).
+			Where("user_id = ? AND lower(name) = lower(?)", account.UserID, account.Name).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 || account.Name == models.OpeningBalancesName {
+			return ErrLedgerAccountExists
+		}
+		return tx.Create(account).Error
+	})
+}
+
+func (r *LedgerRepository) FindEntries(query JournalQuery) (*JournalPage, error) {
+	if query.Limit <= 0 {
+		query.Limit = DefaultUserPageSize
+	}
+	if query.Limit > MaxUserPageSize {
+		query.Limit = MaxUserPageSize
+	}
+
+	db := r.db.Where("user_id = ?", query.UserID)
+	if query.LedgerAccountID != 0 {
+		db = db.Where("id IN (SELECT journal_entry_id FROM postings WHERE ledger_account_id = ?)", query.LedgerAccountID)
+	}
+	if query.Cursor != "" {
+		beforeID, err := decodeIDCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		db = db.Where("id < ?", beforeID)
+	}
+
+	var entries []models.JournalEntry
+	if err := db.Preload("Postings").Order("id DESC").Limit(query.Limit + 1).Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	page := &JournalPage{Entries: entries}
+	if len(entries) > query.Limit {
+		page.Entries = entries[:query.Limit]
+		page.NextCursor = encodeIDCursor(page.Entries[query.Limit-1].ID)
+	}
+	return page, nil
+}
+
+func (r *LedgerRepository) GetEntry(userID int, id uint) (*models.JournalEntry, error) {
+	var entry models.JournalEntry
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).Preload("Postings").First(&entry).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrJournalEntryNotFound
+		}
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// CreateEntry books a manual entry. Like every entry it must balance; it
+// may not post to the ledger accounts of accounts or categories, whose
+// balances follow the finance records.
+func (r *LedgerRepository) CreateEntry(entry *models.JournalEntry) error {
+	entry.OpeningAccountID = nil
+	entry.RecurringRuleID = nil
+	entry.Occurrence = nil
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		ids := make([]uint, len(entry.Postings))
+		for i, posting := range entry.Postings {
+			ids[i] = posting.LedgerAccountID
+		}
+		var linked int64
+		if err := tx.Model(&models.LedgerAccount{}).
+			Where("id IN ? AND (account_id IS NOT NULL OR category_id IS NOT NULL)", ids).
+			Count(&linked).Error; err != nil {
+			return err
+		}
+		if linked > 0 {
+			return ErrLedgerAccountLinked
+		}
+		return createJournalEntry(tx, entry)
+	})
+}
+
+// DeleteEntry removes a manual entry together with its postings.
+func (r *LedgerRepository) DeleteEntry(userID int, id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var entry models.JournalEntry
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ?", id, userID).First(&entry).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrJournalEntryNotFound
+			}
+			return err
+		}
+		managed, err := isManagedEntry(tx, &entry)
+		if err != nil {
+			return err
+		}
+		if managed {
+			return ErrJournalEntryManaged
+		}
+		return tx.Delete(&entry).Error
+	})
+}
+
+// TrialBalance sums the postings of every ledger account of the user made
+// before at.
+func (r *LedgerRepository) TrialBalance(userID int, at time.Time) (*ledger.TrialBalance, error) {
+	balances, err := r.balances(userID, at)
+	if err != nil {
+		return nil, err
+	}
+	return ledger.NewTrialBalance(at, balances), nil
+}
+
+// BalanceSheet reports the user's assets, liabilities and equity as of at.
+func (r *LedgerRepository) BalanceSheet(userID int, at time.Time) (*ledger.BalanceSheet, error) {
+	balances, err := r.balances(userID, at)
+	if err != nil {
+		return nil, err
+	}
+	return ledger.NewBalanceSheet(at, balances), nil
+}
+
+func (r *LedgerRepository) balances(userID int, at time.Time) ([]ledger.Balance, error) {
+	var balances []ledger.Balance
+	err := r.db.Raw(`
+		SELECT la.id AS ledger_account_id, la.name, la.type, p.currency, SUM(p.amount) AS total
+		FROM postings p
+		JOIN journal_entries je ON je.id = p.journal_entry_id
+		JOIN ledger_accounts la ON la.id = p.ledger_account_id
+		WHERE je.user_id = ? AND je.date < ?
+		GROUP BY la.id, la.name, la.type, p.currency
+		ORDER BY array_position(ARRAY[?, ?, ?, ?, ?]::text[], la.type::text), la.name, p.currency`,
+		userID, at,
+		models.LedgerAsset, models.LedgerLiability, models.LedgerEquity, models.LedgerIncome, models.LedgerExpense,
+	).Scan(&balances).Error
+	return balances, err
+}
+
+// isManagedEntry reports whether the entry is maintained by the finance API:
+// it books an opening balance or posts to the ledger account of an account
+// or a category, i.e. it is read as finance records.
+func isManagedEntry(tx *gorm.DB, entry *models.JournalEntry) (bool, error) {
+	if entry.OpeningAccountID != nil {
+		return true, nil
+	}
+	var linked int64
+	err := tx.Model(&models.Posting{}).
+		Joins("JOIN ledger_accounts la ON la.id = postings.ledger_account_id").
+		Where("postings.journal_entry_id = ? AND (la.account_id IS NOT NULL OR la.category_id IS NOT NULL)", entry.ID).
+		Count(&linked).Error
+	return linked > 0, err
+}
+
+// createJournalEntry and replaceJournalEntry are the only ways entries are
+// written, so that every entry in the ledger balances and only posts to its
+// owner's accounts.
+func createJournalEntry(tx *gorm.DB, entry *models.JournalEntry) error {
+	if err := checkJournalEntry(tx, entry); err != nil {
+		return err
+	}
+	return tx.Create(entry).Error
+}
+
+// replaceJournalEntry rewrites the postings and description of an existing
+// entry. Postings with an ID are updated in place and keep it, postings
+// without one are added and the entry's other postings are removed.
+func replaceJournalEntry(tx *gorm.DB, entry *models.JournalEntry) error {
+	if err := checkJournalEntry(tx, entry); err != nil {
+		return err
+	}
+
+	var keep []uint
+	for _, posting := range entry.Postings {
+		if posting.ID != 0 {
+			keep = append(keep, posting.ID)
+		}
+	}
+	stale := tx.Where("journal_entry_id = ?", entry.ID)
+	if len(keep) > 0 {
+		stale = stale.Where("id NOT IN ?", keep)
+	}
+	if err := stale.Delete(&models.Posting{}).Error; err != nil {
+		return err
+	}
+
+	for i := range entry.Postings {
+		posting := &entry.Postings[i]
+		posting.JournalEntryID = entry.ID
+		if posting.ID == 0 {
+			if err := tx.Create(posting).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		if err := tx.Model(posting).Select("LedgerAccountID", "Amount", "Currency", "Note").
+			Updates(posting).Error; err != nil {
+			return err
+		}
+	}
+
+	return tx.Model(&models.JournalEntry{ID: entry.ID}).
+		Updates(map[string]any{"description": entry.Description}).Error
+}
+
+func checkJournalEntry(tx *gorm.DB, entry *models.JournalEntry) error {
+	if err := ledger.Validate(entry.Postings); err != nil {
+		return err
+	}
+
+	ids := map[uint]struct{}{}
+	for _, posting := range entry.Postings {
+		ids[posting.LedgerAccountID] = struct{}{}
+	}
+	accountIDs := make([]uint, 0, len(ids))
+	for id := range ids {
+		accountIDs = append(accountIDs, id)
+	}
+	var owned int64
+	if err := tx.Model(&models.LedgerAccount{}).
+		Where("id IN ? AND user_id = ?", accountIDs, entry.UserID).Count(&owned).Error; err != nil {
+		return err
+	}
+	if owned != int64(len(accountIDs)) {
+		return ErrLedgerAccountNotFound
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"go-finance-tracker/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+type LoginAttemptRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginAttemptRepository(db *gorm.DB) *LoginAttemptRepository {
+	return &LoginAttemptRepository{db: db}
+}
+
+// CountFailuresFromIP counts failed logins from ip since the given time,
+// across all usernames.
+func (r *LoginAttemptRepository) CountFailuresFromIP(ip string, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.LoginAttempt{}).
+		Where("ip = ? AND succeeded = false AND created_at >= ?", ip, since).
+		Count(&count).Error
+	return count, err
+}
+
+// RecordFailure stores a failed attempt and, when it names an existing user,
+// counts it against the account. The failure that reaches maxFailures locks
+// the account for lockFor and restarts the count; the end of that lock is
+// returned so the owner can be told.
+func (r *LoginAttemptRepository) RecordFailure(attempt *models.LoginAttempt, maxFailures int, lockFor time.Duration) (*time.Time, error) {
+	var lockedUntil *time.Time
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		attempt.Succeeded = false
+		if err := tx.Create(attempt).Error; err != nil {
+			return err
+		}
+		if attempt.UserID == nil {
+			return nil
+		}
+
+		var user models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "failed_logins").First(&user, *attempt.UserID).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		updates := map[string]any{
+			"failed_logins":        user.FailedLogins + 1,
+			"last_failed_login_at": now,
+		}
+		if user.FailedLogins+1 >= maxFailures {
+			until := now.Add(lockFor)
+			lockedUntil = &until
+			updates["failed_logins"] = 0
+			updates["locked_until"] = until
+		}
+		return tx.Model(&user).Updates(updates).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return lockedUntil, nil
+}
+
+// RecordSuccess stores a successful attempt and clears the user's failure
+// count.
+func (r *LoginAttemptRepository) RecordSuccess(attempt *models.LoginAttempt) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		attempt.Succeeded = true
+		if err := tx.Create(attempt).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.User{}).Where("id = ?", attempt.UserID).Updates(map[string]any{
+			"failed_logins":        0,
+			"last_failed_login_at": nil,
+			"locked_until":         nil,
+		}).Error
+	})
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"errors"
+	"go-finance-tracker/internal/models"
+	"gorm.io/gorm"
+	"time"
+)
+
+var (
+	ErrMFAAlreadyEnabled = errors.New("two-factor authentication is already enabled")
+	ErrMFANotEnabled     = errors.New("two-factor authentication is not enabled")
+)
+
+type MFARepository struct {
+	db *gorm.DB
+}
+
+func NewMFARepository(db *gorm.DB) *MFARepository {
+	return &MFARepository{db: db}
+}
+
+// SetPendingSecret stores a new encrypted TOTP secret for a user who has not
+// enabled two-factor authentication yet, replacing any earlier unconfirmed
+// one.
+func (r *MFARepository) SetPendingSecret(userID uint, secret string) error {
+	result := r.db.Model(&models.User{}).
+		Where("id = ? AND totp_enabled_at IS NULL", userID).
+		Update("totp_secret", secret)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrMFAAlreadyEnabled
+	}
+	return nil
+}
+
+// Enable turns on two-factor authentication with the pending secret after
+// the code for step was confirmed, and stores the recovery code hashes.
+func (r *MFARepository) Enable(userID uint, step int64, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.User{}).
+			Where("id = ? AND totp_enabled_at IS NULL AND totp_secret <> ''", userID).
+			Updates(map[string]any{
+				"totp_enabled_at": time.Now(),
+				"totp_last_step":  step,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrMFAAlreadyEnabled
+		}
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+// Disable turns off two-factor authentication and deletes the secret and
+// recovery codes.
+func (r *MFARepository) Disable(userID uint, entry *models.AuditLog) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("id = ?", userID).Updates(map[string]any{
+			"totp_secret":     "",
+			"totp_enabled_at": nil,
+			"totp_last_step":  0,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return recordAudit(tx, entry)
+	})
+}
+
+// UseStep records step as the last accepted TOTP code. It reports false when
+// a code for the same or a later step was already accepted, so every code
+// works only once.
+func (r *MFARepository) UseStep(userID uint, step int64) (bool, error) {
+	result := r.db.Model(&models.User{}).
+		Where("id = ? AND totp_last_step < ?", userID, step).
+		Update("totp_last_step", step)
+	return result.RowsAffected == 1, result.Error
+}
+
+// UseRecoveryCode marks the unused recovery code with codeHash as used. It
+// reports false when the user has no such code.
+func (r *MFARepository) UseRecoveryCode(userID uint, codeHash string) (bool, error) {
+	result := r.db.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	return result.RowsAffected == 1, result.Error
+}
+
+// ReplaceRecoveryCodes invalidates the user's recovery codes and stores new
+// ones.
+func (r *MFARepository) ReplaceRecoveryCodes(userID uint, codeHashes []string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.User{}).
+			Where("id = ? AND totp_enabled_at IS NOT NULL", userID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return ErrMFANotEnabled
+		}
+		return replaceRecoveryCodes(tx, userID, codeHashes)
+	})
+}
+
+func replaceRecoveryCodes(tx *gorm.DB, userID uint, codeHashes []string) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.RecoveryCode{}).Error; err != nil {
+		return err
+	}
+	codes := make([]models.RecoveryCode, len(codeHashes))
+	for i, hash := range codeHashes {
+		codes[i] = models.RecoveryCode{UserID: userID, CodeHash: hash}
+	}
+	return tx.Create(&codes).Error
+}
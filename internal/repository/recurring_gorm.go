@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"errors"
+	"go-finance-tracker/internal/models"
+	"go-finance-tracker/pkg/logger"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+// maxOccurrencesPerRun bounds the catch-up work done for one rule in a single
+// transaction; the remainder is picked up by the next run.
+const maxOccurrencesPerRun = 100
+
+// A rule that fails is retried after failureBackoff, doubling with every
+// consecutive failure up to maxFailureBackoff.
+const (
+	failureBackoff    = time.Minute
+	maxFailureBackoff = 24 * time.Hour
+)
+
+var (
+	ErrRecurringRuleNotFound = errors.New("recurring rule not found")
+)
+
+type RecurringRepository struct {
+	db *gorm.DB
+}
+
+func NewRecurringRepository(db *gorm.DB) *RecurringRepository {
+	return &RecurringRepository{db: db}
+}
+
+func (r *RecurringRepository) GetAll(userID int) ([]models.RecurringRule, error) {
+	var rules []models.RecurringRule
+	if err := r.db.Where("user_id = ?", userID).Order("id").Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *RecurringRepository) GetByID(userID int, id uint) (*models.RecurringRule, error) {
+	var rule models.RecurringRule
+	if err := r.db.Where("id = ? AND user_id = ?", id, userID).First(&rule).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRecurringRuleNotFound
+		}
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// Create stores the rule and schedules its first occurrence. Rules cannot
+// repeat transfers, since occurrences are materialized one record at a time.
+func (r *RecurringRepository) Create(rule *models.RecurringRule) error {
+	if _, err := signedAmount(r.db, &models.FinanceRecord{TransactionTypeID: rule.TransactionTypeID}); err != nil {
+		return err
+	}
+	rule.NextOccurrence = 0
+	rule.ScheduleNext()
+	return r.db.Create(rule).Error
+}
+
+// Update changes what future occurrences record and when the rule ends. The
+// schedule itself (frequency, interval, start) is fixed once created so that
+// already materialized occurrences keep their meaning.
+func (r *RecurringRepository) Update(rule *models.RecurringRule) error {
+	if _, err := signedAmount(r.db, &models.FinanceRecord{TransactionTypeID: rule.TransactionTypeID}); err != nil {
+		return err
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var current models.RecurringRule
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ?", rule.ID, rule.UserID).First(&current).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrRecurringRuleNotFound
+			}
+			return err
+		}
+
+		if rule.AccountID != 0 {
+			current.AccountID = rule.AccountID
+		}
+		current.Amount = rule.Amount
+		current.TransactionTypeID = rule.TransactionTypeID
+		current.CategoryID = rule.CategoryID
+		current.Note = rule.Note
+		current.EndDate = rule.EndDate
+		current.Count = rule.Count
+		current.Failures = 0
+		current.ScheduleNext()
+
+		if err := tx.Model(&current).
+			Select("AccountID", "Amount", "TransactionTypeID", "CategoryID", "Note", "EndDate", "Count", "NextRunAt", "Failures").
+			Updates(&current).Error; err != nil {
+			return err
+		}
+		*rule = current
+		return nil
+	})
+}
+
+func (r *RecurringRepository) Delete(userID int, id uint) error {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&models.RecurringRule{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrRecurringRuleNotFound
+	}
+	return nil
+}
+
+// MaterializeDue creates the finance records of every occurrence due at now
+// for up to batch rules and returns how many rules it claimed and how many
+// records were created. Fewer claimed rules than batch means nothing else is
+// due.
+//
+// Rules are claimed with FOR UPDATE SKIP LOCKED so several replicas can run
+// the scheduler at once without processing the same rule, and journal
+// entries carry a unique (rule, occurrence) key so a crash between commit
+// and bookkeeping never duplicates an occurrence. A rule that fails is
+// postponed with an exponential backoff, so that broken rules cannot fill
+// every batch.
+func (r *RecurringRepository) MaterializeDue(now time.Time, batch int) (claimed, created int, err error) {
+	err = r.db.Transaction(func(tx *gorm.DB) error {
+		var rules []models.RecurringRule
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("next_run_at IS NOT NULL AND next_run_at <= ?", now).
+			Order("next_run_at").Limit(batch).
+			Find(&rules).Error; err != nil {
+			return err
+		}
+		claimed = len(rules)
+
+		for i := range rules {
+			// A savepoint per rule keeps one broken rule (e.g. a deleted
+			// transaction type) from blocking the rest of the batch.
+			var n int
+			err := tx.Transaction(func(ruleTx *gorm.DB) error {
+				var err error
+				n, err = materializeRule(ruleTx, &rules[i], now)
+				return err
+			})
+			if err != nil {
+				logger.GetLogger().Errorf("Failed to materialize recurring rule %d: %s", rules[i].ID, err)
+				if err := postponeRule(tx, &rules[i], now); err != nil {
+					return err
+				}
+				continue
+			}
+			created += n
+		}
+		return nil
+	})
+	return claimed, created, err
+}
+
+// postponeRule records a failed run of rule and moves its next run out by
+// the backoff for its number of consecutive failures.
+func postponeRule(tx *gorm.DB, rule *models.RecurringRule, now time.Time) error {
+	backoff := failureBackoff
+	for i := 0; i < rule.Failures && backoff < maxFailureBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxFailureBackoff {
+		backoff = maxFailureBackoff
+	}
+
+	return tx.Model(&models.RecurringRule{}).Where("id = ?", rule.ID).UpdateColumns(map[string]any{
+		"failures":    gorm.Expr("failures + 1"),
+		"next_run_at": now.Add(backoff),
+	}).Error
+}
+
+func materializeRule(tx *gorm.DB, rule *models.RecurringRule, now time.Time) (int, error) {
+	created := 0
+	for n := 0; n < maxOccurrencesPerRun; n++ {
+		occurrence, ok := rule.OccurrenceAt(rule.NextOccurrence)
+		if !ok || occurrence.After(now) {
+			break
+		}
+
+		index := rule.NextOccurrence
+		ruleID := rule.ID
+		record := models.FinanceRecord{
+			UserID:            rule.UserID,
+			AccountID:         rule.AccountID,
+			Amount:            rule.Amount,
+			TransactionTypeID: rule.TransactionTypeID,
+			CategoryID:        rule.CategoryID,
+			Note:              rule.Note,
+			RecurringRuleID:   &ruleID,
+			Occurrence:        &index,
+		}
+		record.CreatedAt = occurrence
+
+		inserted, err := createFinanceRecord(tx, &record)
+		if err != nil {
+			return created, err
+		}
+		if inserted {
+			created++
+		}
+		rule.NextOccurrence++
+	}
+
+	rule.Failures = 0
+	rule.ScheduleNext()
+	return created, tx.Model(rule).Select("NextOccurrence", "NextRunAt", "Failures").Updates(rule).Error
+}
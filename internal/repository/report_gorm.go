@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"errors"
+	"go-finance-tracker/internal/models"
+	"go-finance-tracker/pkg/money"
+	"gorm.io/gorm"
+	"time"
+)
+
+var (
+	ErrInvalidPeriod = errors.New("invalid grouping period")
+)
+
+type ReportPeriod string
+
+const (
+	PeriodDay   ReportPeriod = "day"
+	PeriodWeek  ReportPeriod = "week"
+	PeriodMonth ReportPeriod = "month"
+	PeriodYear  ReportPeriod = "year"
+)
+
+func (p ReportPeriod) Valid() bool {
+	switch p {
+	case PeriodDay, PeriodWeek, PeriodMonth, PeriodYear:
+		return true
+	}
+	return false
+}
+
+// SummaryQuery selects the records of UserID created in [From, To) and
+// groups them into periods whose boundaries are computed in Location.
+// Amounts are converted to Currency, the user's base currency when empty.
+type SummaryQuery struct {
+	UserID   int
+	From     time.Time
+	To       time.Time
+	GroupBy  ReportPeriod
+	Location *time.Location
+	Currency money.Currency
+}
+
+// PeriodSummary totals the records of one period. Unconverted counts the
+// records left out because no exchange rate to the report currency was
+// known on their day.
+type PeriodSummary struct {
+	Start       time.Time    `json:"start"`
+	Income      money.Amount `json:"income"`
+	Expense     money.Amount `json:"expense"`
+	Net         money.Amount `json:"net"`
+	Unconverted int64        `json:"unconverted"`
+}
+
+type CategorySummary struct {
+	CategoryID uint         `json:"categoryID"`
+	Name       string       `json:"name"`
+	Income     money.Amount `json:"income"`
+	Expense    money.Amount `json:"expense"`
+	Net        money.Amount `json:"net"`
+}
+
+type Summary struct {
+	From        time.Time         `json:"from"`
+	To          time.Time         `json:"to"`
+	GroupBy     ReportPeriod      `json:"groupBy"`
+	Timezone    string            `json:"timezone"`
+	Currency    money.Currency    `json:"currency"`
+	Income      money.Amount      `json:"income"`
+	Expense     money.Amount      `json:"expense"`
+	Net         money.Amount      `json:"net"`
+	Unconverted int64             `json:"unconverted"`
+	Periods     []PeriodSummary   `json:"periods"`
+	Categories  []CategorySummary `json:"categories"`
+}
+
+type ReportRepository struct {
+	db *gorm.DB
+}
+
+func NewReportRepository(db *gorm.DB) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+// Summary aggregates income and expense in SQL. Only periods that contain at
+// least one record are returned. Transfers between accounts are neither, so
+// they are left out. Every record is converted with the rate of its own day,
+// so totals do not change when rates move later. Split records count each
+// line under its own category.
+func (r *ReportRepository) Summary(query SummaryQuery) (*Summary, error) {
+	if !query.GroupBy.Valid() {
+		return nil, ErrInvalidPeriod
+	}
+	if query.Location == nil {
+		query.Location = time.UTC
+	}
+	timezone := query.Location.String()
+
+	if query.Currency == "" {
+		currency, err := baseCurrency(r.db, uint(query.UserID))
+		if err != nil {
+			return nil, err
+		}
+		query.Currency = currency
+	}
+
+	summary := &Summary{
+		From:       query.From.In(query.Location),
+		To:         query.To.In(query.Location),
+		GroupBy:    query.GroupBy,
+		Timezone:   timezone,
+		Currency:   query.Currency,
+		Periods:    []PeriodSummary{},
+		Categories: []CategorySummary{},
+	}
+
+	records := `
+		WITH records AS (
+			SELECT fr.id, fr.created_at, COALESCE(fs.category_id, fr.category_id) AS category_id, tt.name AS type,
+				ROUND(COALESCE(fs.amount, fr.amount) * cv.rate, 2) AS amount
+			FROM finance_records fr
+			JOIN transaction_types tt ON tt.id = fr.transaction_type_id
+			LEFT JOIN finance_splits fs ON fs.finance_record_id = fr.id` + convertJoin + `
+			WHERE fr.user_id = ? AND fr.deleted_at IS NULL AND fr.created_at >= ? AND fr.created_at < ?
+				AND tt.name IN (?, ?)
+		)`
+	recordArgs := append(convertArgs(query.Currency, timezone),
+		query.UserID, query.From, query.To, models.Income, models.Expense)
+
+	// Truncate the local wall-clock time, then convert the bucket start back
+	// to an absolute instant so that DST shifts land on the right day.
+	if err := r.db.Raw(records+`
+		SELECT date_trunc(?::text, created_at AT TIME ZONE ?::text) AT TIME ZONE ?::text AS start,
+			COALESCE(SUM(amount) FILTER (WHERE type = ?), 0) AS income,
+			COALESCE(SUM(amount) FILTER (WHERE type = ?), 0) AS expense,
+			COUNT(DISTINCT id) FILTER (WHERE amount IS NULL) AS unconverted
+		FROM records
+		GROUP BY 1
+		ORDER BY 1`,
+		append(recordArgs, string(query.GroupBy), timezone, timezone, models.Income, models.Expense)...,
+	).Scan(&summary.Periods).Error; err != nil {
+		return nil, err
+	}
+
+	if err := r.db.Raw(records+`
+		SELECT c.id AS category_id, c.name,
+			COALESCE(SUM(rec.amount) FILTER (WHERE rec.type = ?), 0) AS income,
+			COALESCE(SUM(rec.amount) FILTER (WHERE rec.type = ?), 0) AS expense
+		FROM records rec
+		JOIN categories c ON c.id = rec.category_id
+		GROUP BY c.id, c.name
+		ORDER BY c.name`,
+		append(recordArgs, models.Income, models.Expense)...,
+	).Scan(&summary.Categories).Error; err != nil {
+		return nil, err
+	}
+
+	for i := range summary.Periods {
+		period := &summary.Periods[i]
+		period.Start = period.Start.In(query.Location)
+		period.Net = period.Income.Sub(period.Expense)
+		summary.Income = summary.Income.Add(period.Income)
+		summary.Expense = summary.Expense.Add(period.Expense)
+		summary.Unconverted += period.Unconverted
+	}
+	summary.Net = summary.Income.Sub(summary.Expense)
+
+	for i := range summary.Categories {
+		category := &summary.Categories[i]
+		category.Net = category.Income.Sub(category.Expense)
+	}
+
+	return summary, nil
+}
@@ -1,10 +1,15 @@
 package repository
 
 import (
+	"errors"
 	"go-finance-tracker/internal/models"
 	"gorm.io/gorm"
 )
 
+var (
+	ErrRoleNotFound = errors.New("role not found")
+)
+
 type RoleRepository struct {
 	db *gorm.DB
 }
@@ -24,7 +29,49 @@ func (rr *RoleRepository) GetByID(id uint) (*models.Role, error) {
 func (rr *RoleRepository) GetByName(name string) (*models.Role, error) {
 	var role models.Role
 	if err := rr.db.Where("name = ?", name).First(&role).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoleNotFound
+		}
 		return nil, err
 	}
 	return &role, nil
 }
+
+// GetPermissionNames returns the distinct permissions granted to any of the
+// roles.
+func (rr *RoleRepository) GetPermissionNames(roleIDs []uint) ([]string, error) {
+	names := []string{}
+	if len(roleIDs) == 0 {
+		return names, nil
+	}
+
+	if err := rr.db.Model(&models.Permission{}).
+		Distinct("permissions.name").
+		Joins("JOIN role_permissions ON role_permissions.permission_id = permissions.id").
+		Where("role_permissions.role_id IN ?", roleIDs).
+		Order("permissions.name").
+		Pluck("permissions.name", &names).Error; err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// Assign grants role to the user. Granting a role the user already has is a
+// no-op.
+func (rr *RoleRepository) Assign(userID uint, role *models.Role, entry *models.AuditLog) error {
+	return rr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{Model: gorm.Model{ID: userID}}).Association("Roles").Append(role); err != nil {
+			return err
+		}
+		return recordAudit(tx, entry)
+	})
+}
+
+func (rr *RoleRepository) Revoke(userID uint, role *models.Role, entry *models.AuditLog) error {
+	return rr.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{Model: gorm.Model{ID: userID}}).Association("Roles").Delete(role); err != nil {
+			return err
+		}
+		return recordAudit(tx, entry)
+	})
+}
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"errors"
+	"go-finance-tracker/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+var (
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrInvalidRefreshToken = errors.New("refresh token is invalid or expired")
+	ErrRefreshTokenReused  = errors.New("refresh token was already used, the session has been revoked")
+)
+
+type SessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) *SessionRepository {
+	return &SessionRepository{db: db}
+}
+
+// Create stores a new session together with its first refresh token.
+func (r *SessionRepository) Create(session *models.Session, tokenHash string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(session).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.RefreshToken{SessionID: session.ID, TokenHash: tokenHash}).Error
+	})
+}
+
+// Rotate exchanges the refresh token tokenHash for newTokenHash and records
+// where the session was used from. The session's expiry slides to expiresAt.
+// A token that was already rotated revokes the session and returns
+// ErrRefreshTokenReused.
+func (r *SessionRepository) Rotate(tokenHash, newTokenHash, ip, userAgent string, expiresAt time.Time) (*models.Session, error) {
+	var session models.Session
+	reused := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var token models.RefreshToken
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ?", tokenHash).First(&token).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidRefreshToken
+			}
+			return err
+		}
+
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&session, token.SessionID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidRefreshToken
+			}
+			return err
+		}
+
+		now := time.Now()
+		if !session.IsActive(now) {
+			return ErrInvalidRefreshToken
+		}
+
+		if token.UsedAt != nil {
+			reused = true
+			return tx.Model(&session).Update("revoked_at", now).Error
+		}
+
+		if err := tx.Model(&token).Update("used_at", now).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(&models.RefreshToken{SessionID: session.ID, TokenHash: newTokenHash}).Error; err != nil {
+			return err
+		}
+
+		session.IP = ip
+		session.UserAgent = userAgent
+		session.LastUsedAt = now
+		session.ExpiresAt = expiresAt
+		return tx.Model(&session).Select("ip", "user_agent", "last_used_at", "expires_at").Updates(&session).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	if reused {
+		return nil, ErrRefreshTokenReused
+	}
+	return &session, nil
+}
+
+func (r *SessionRepository) GetByID(id uint) (*models.Session, error) {
+	var session models.Session
+	if err := r.db.First(&session, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, err
+	}
+	return &session, nil
+}
+
+// GetActive returns the user's sessions that are neither revoked nor
+// expired, most recently used first.
+func (r *SessionRepository) GetActive(userID int) ([]models.Session, error) {
+	var sessions []models.Session
+	if err := r.db.Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at DESC").Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+func (r *SessionRepository) Revoke(userID int, id uint) error {
+	result := r.db.Model(&models.Session{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
+
+// revokeUserSessions revokes every active session of the user, e.g. after a
+// password reset.
+func revokeUserSessions(tx *gorm.DB, userID uint) error {
+	return tx.Model(&models.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
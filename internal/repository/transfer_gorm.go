@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"errors"
+	"go-finance-tracker/internal/models"
+	"go-finance-tracker/pkg/money"
+	"gorm.io/gorm"
+	"time"
+)
+
+var (
+	ErrSameAccount              = errors.New("cannot transfer to the same account")
+	ErrTransferCurrencyMismatch = errors.New("accounts of a transfer must have the same currency")
+	ErrTransferCategoryMissing  = errors.New("the Transfers category is missing, run the seed")
+)
+
+// NewTransfer describes money moving from one of the user's accounts to
+// another.
+type NewTransfer struct {
+	UserID        int
+	FromAccountID uint
+	ToAccountID   uint
+	Amount        money.Amount
+	Note          string
+}
+
+// Transfer is the pair of records a transfer creates: Out leaves the source
+// account with a negative amount, In arrives at the destination.
+type Transfer struct {
+	Out models.FinanceRecord `json:"out"`
+	In  models.FinanceRecord `json:"in"`
+}
+
+type TransferRepository struct {
+	db *gorm.DB
+}
+
+func NewTransferRepository(db *gorm.DB) *TransferRepository {
+	return &TransferRepository{db: db}
+}
+
+// Create books a transfer as one journal entry posting to both accounts and
+// returns its two halves as read back from the journal. The user's total
+// balance does not change.
+func (r *TransferRepository) Create(transfer NewTransfer) (*Transfer, error) {
+	if transfer.FromAccountID == transfer.ToAccountID {
+		return nil, ErrSameAccount
+	}
+
+	var result Transfer
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		accounts := make([]*models.Account, 2)
+		for i, id := range []uint{transfer.FromAccountID, transfer.ToAccountID} {
+			var account models.Account
+			if err := tx.Where("id = ? AND user_id = ?", id, transfer.UserID).First(&account).Error; err != nil {
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					return ErrAccountNotFound
+				}
+				return err
+			}
+			if account.Archived {
+				return ErrAccountArchived
+			}
+			accounts[i] = &account
+		}
+		if accounts[0].Currency != accounts[1].Currency {
+			return ErrTransferCurrencyMismatch
+		}
+
+		// Both halves are read back under the transfer type and the
+		// Transfers category, so both must exist.
+		var transactionType models.TransactionType
+		if err := tx.Where("name = ?", models.Transfer).First(&transactionType).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUnknownTransactionType
+			}
+			return err
+		}
+		var category models.Category
+		if err := tx.Where("user_id IS NULL AND parent_id IS NULL AND name = ?", models.TransferCategoryName).
+			First(&category).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrTransferCategoryMissing
+			}
+			return err
+		}
+
+		from, err := accountLedgerAccount(tx, transfer.FromAccountID)
+		if err != nil {
+			return err
+		}
+		to, err := accountLedgerAccount(tx, transfer.ToAccountID)
+		if err != nil {
+			return err
+		}
+		entry := models.JournalEntry{
+			UserID:      uint(transfer.UserID),
+			Date:        time.Now(),
+			Description: transfer.Note,
+			Postings: []models.Posting{
+				{LedgerAccountID: from.ID, Amount: transfer.Amount.Neg(), Currency: accounts[0].Currency},
+				{LedgerAccountID: to.ID, Amount: transfer.Amount, Currency: accounts[1].Currency},
+			},
+		}
+		if err := createJournalEntry(tx, &entry); err != nil {
+			return err
+		}
+
+		records, err := loadFinanceRecords(tx, entry.ID)
+		if err != nil {
+			return err
+		}
+		if len(records) != 2 {
+			return ErrFinanceRecordNotFound
+		}
+		result.Out, result.In = records[0], records[1]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
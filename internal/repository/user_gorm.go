@@ -1,10 +1,11 @@
 package repository
 
 import (
-	"database/sql"
 	"errors"
 	"go-finance-tracker/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
 )
 
 var (
@@ -19,17 +20,40 @@ func NewUserRepository(db *gorm.DB) *UserRepository {
 	return &UserRepository{db: db}
 }
 
+// CreateUser stores the user together with their default account.
 func (ur *UserRepository) CreateUser(user *models.User) error {
-	if err := ur.db.Create(&user).Error; err != nil {
-		return err
+	return ur.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		account := models.NewDefaultAccount(user.ID)
+		if err := tx.Create(&account).Error; err != nil {
+			return err
+		}
+		return syncAccountLedger(tx, &account)
+	})
+}
+
+func (ur *UserRepository) GetUserByID(id uint) (*models.User, error) {
+	var user models.User
+	if err := ur.db.Preload("Roles").First(&user, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
 	}
 
-	return nil
+	return &user, nil
 }
 
-func (ur *UserRepository) GetUserByID(id uint) (*models.User, error) {
+// GetUserIncludingDeleted is GetUserByID for administrators, who also need
+// to see soft-deleted users.
+func (ur *UserRepository) GetUserIncludingDeleted(id uint) (*models.User, error) {
 	var user models.User
-	if err := ur.db.First(&user, id).Error; err != nil {
+	if err := ur.db.Unscoped().Preload("Roles").First(&user, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
 		return nil, err
 	}
 
@@ -39,7 +63,19 @@ func (ur *UserRepository) GetUserByID(id uint) (*models.User, error) {
 func (ur *UserRepository) GetUserByUsername(username string) (*models.User, error) {
 	var user models.User
 	if err := ur.db.Preload("Roles").First(&user, "username = ?", username).Error; err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+func (ur *UserRepository) GetUserByEmail(email string) (*models.User, error) {
+	var user models.User
+	if err := ur.db.First(&user, "lower(email) = lower(?)", email).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, ErrUserNotFound
 		}
 		return nil, err
@@ -57,31 +93,134 @@ func (ur *UserRepository) GetAllUsers() ([]models.User, error) {
 	return users, nil
 }
 
-func (ur *UserRepository) DeleteUser(id uint) error {
-	var user models.User
-	if err := ur.db.First(&user, id).Error; err != nil {
-		return err
-	}
+// DeleteUser soft-deletes the user and revokes their sessions.
+func (ur *UserRepository) DeleteUser(id uint, entry *models.AuditLog) error {
+	return ur.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.User{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrUserNotFound
+		}
+		if err := revokeUserSessions(tx, id); err != nil {
+			return err
+		}
+		return recordAudit(tx, entry)
+	})
+}
 
-	if err := ur.db.Delete(&user).Error; err != nil {
-		return err
-	}
+// RestoreUser undoes DeleteUser.
+func (ur *UserRepository) RestoreUser(id uint, entry *models.AuditLog) error {
+	return ur.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Model(&models.User{}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Update("deleted_at", nil)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrUserNotFound
+		}
+		return recordAudit(tx, entry)
+	})
+}
 
-	return nil
+// UpdateUser saves the user's profile fields: name, surname, email and base
+// currency, together with the email verification state. A new base currency
+// converts the stored balance to it.
+func (ur *UserRepository) UpdateUser(user *models.User, entry *models.AuditLog) error {
+	return ur.db.Transaction(func(tx *gorm.DB) error {
+		var current models.User
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "base_currency").
+			First(&current, user.ID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrUserNotFound
+			}
+			return err
+		}
+
+		if err := tx.Model(user).Select("Name", "Surname", "Email", "BaseCurrency", "EmailVerifiedAt").
+			Updates(user).Error; err != nil {
+			return err
+		}
+		if current.BaseCurrency != user.BaseCurrency {
+			if err := recomputeBalance(tx, user.ID); err != nil {
+				return err
+			}
+		}
+		return recordAudit(tx, entry)
+	})
 }
 
-//func (ur *UserRepository) UpdateUser(id uint, updateForm forms.UpdateForm) error {
-//	var user models.User
-//	if err := ur.db.First(&user, id).Error; err != nil {
-//		return err
-//	}
-//
-//	user.Name = updateForm.Name
-//	user.Surname = updateForm.Surname
-//
-//	if err := ur.db.Save(&user).Error; err != nil {
-//		return err
-//	}
-//
-//	return nil
-//}
+// ChangePassword stores a new password hash and revokes every session of the
+// user except keepSessionID.
+func (ur *UserRepository) ChangePassword(id uint, passwordHash string, keepSessionID uint) error {
+	return ur.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.User{}).Where("id = ?", id).Updates(map[string]any{
+			"password":            passwordHash,
+			"password_changed_at": time.Now(),
+			"token_version":       gorm.Expr("token_version + 1"),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrUserNotFound
+		}
+		return tx.Model(&models.Session{}).
+			Where("user_id = ? AND id <> ? AND revoked_at IS NULL", id, keepSessionID).
+			Update("revoked_at", time.Now()).Error
+	})
+}
+
+// SetLocked locks or unlocks the account. Locking also revokes the user's
+// sessions; unlocking also lifts a lockout caused by failed logins.
+func (ur *UserRepository) SetLocked(id uint, locked bool, entry *models.AuditLog) error {
+	return ur.db.Transaction(func(tx *gorm.DB) error {
+		updates := map[string]any{"locked_at": nil}
+		if locked {
+			updates["locked_at"] = time.Now()
+		} else {
+			updates["failed_logins"] = 0
+			updates["last_failed_login_at"] = nil
+			updates["locked_until"] = nil
+		}
+
+		result := tx.Model(&models.User{}).Where("id = ?", id).Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrUserNotFound
+		}
+		if locked {
+			if err := revokeUserSessions(tx, id); err != nil {
+				return err
+			}
+		}
+		return recordAudit(tx, entry)
+	})
+}
+
+// ResetCredentials replaces the password hash and invalidates every token
+// and session of the user.
+func (ur *UserRepository) ResetCredentials(id uint, passwordHash string, entry *models.AuditLog) error {
+	return ur.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.User{}).Where("id = ?", id).Updates(map[string]any{
+			"password":            passwordHash,
+			"password_changed_at": time.Now(),
+			"token_version":       gorm.Expr("token_version + 1"),
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrUserNotFound
+		}
+		if err := revokeUserSessions(tx, id); err != nil {
+			return err
+		}
+		return recordAudit(tx, entry)
+	})
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"encoding/base64"
+	"go-finance-tracker/internal/models"
+	"gorm.io/gorm"
+	"strconv"
+)
+
+const (
+	DefaultUserPageSize = 20
+	MaxUserPageSize     = 100
+)
+
+// UserQuery describes a page of users for administrators, ordered by ID.
+type UserQuery struct {
+	// Search matches a part of the username or email, case-insensitively.
+	Search string
+	// Deleted lists soft-deleted users instead of active ones.
+	Deleted bool
+	Cursor  string
+	Limit   int
+}
+
+type UserPage struct {
+	Users      []models.User `json:"users"`
+	NextCursor string        `json:"nextCursor,omitempty"`
+	Total      int64         `json:"total"`
+}
+
+func (q *UserQuery) normalize() {
+	if q.Limit <= 0 {
+		q.Limit = DefaultUserPageSize
+	}
+	if q.Limit > MaxUserPageSize {
+		q.Limit = MaxUserPageSize
+	}
+}
+
+func (q *UserQuery) apply(db *gorm.DB) *gorm.DB {
+	if q.Deleted {
+		db = db.Unscoped().Where("deleted_at IS NOT NULL")
+	}
+	if q.Search != "" {
+		pattern := "%" + escapeLike(q.Search) + "%"
+		db = db.Where("(username ILIKE ? OR email ILIKE ?)", pattern, pattern)
+	}
+	return db
+}
+
+func (r *UserRepository) SearchUsers(query UserQuery) (*UserPage, error) {
+	query.normalize()
+
+	var total int64
+	if err := query.apply(r.db.Model(&models.User{})).Count(&total).Error; err != nil {
+		return nil, err
+	}
+
+	db := query.apply(r.db.Model(&models.User{}))
+	if query.Cursor != "" {
+		afterID, err := decodeIDCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		db = db.Where("id > ?", afterID)
+	}
+
+	var users []models.User
+	if err := db.Order("id").Limit(query.Limit + 1).Preload("Roles").Find(&users).Error; err != nil {
+		return nil, err
+	}
+
+	page := &UserPage{Users: users, Total: total}
+	if len(users) > query.Limit {
+		page.Users = users[:query.Limit]
+		page.NextCursor = encodeIDCursor(page.Users[query.Limit-1].ID)
+	}
+	return page, nil
+}
+
+// encodeIDCursor and decodeIDCursor implement cursors for lists that are
+// ordered by ID alone.
+func encodeIDCursor(id uint) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatUint(uint64(id), 10)))
+}
+
+func decodeIDCursor(cursor string) (uint, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	id, err := strconv.ParseUint(string(raw), 10, 64)
+	if err != nil {
+		return 0, ErrInvalidCursor
+	}
+	return uint(id), nil
+}
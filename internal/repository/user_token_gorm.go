@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"errors"
+	"go-finance-tracker/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+)
+
+var (
+	ErrInvalidUserToken = errors.New("token is invalid or expired")
+)
+
+type UserTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewUserTokenRepository(db *gorm.DB) *UserTokenRepository {
+	return &UserTokenRepository{db: db}
+}
+
+// Issue stores a new token and invalidates the user's earlier unused tokens
+// of the same purpose, so only the latest email link works.
+func (r *UserTokenRepository) Issue(token *models.UserToken) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.UserToken{}).
+			Where("user_id = ? AND purpose = ? AND used_at IS NULL", token.UserID, token.Purpose).
+			Update("used_at", time.Now()).Error; err != nil {
+			return err
+		}
+		return tx.Create(token).Error
+	})
+}
+
+// ResetPassword consumes a password reset token and stores the new password
+// hash in one transaction. Raising TokenVersion invalidates every token
+// issued before the reset, and all of the user's sessions are revoked.
+func (r *UserTokenRepository) ResetPassword(tokenHash, passwordHash string) (uint, error) {
+	var userID uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		token, err := consumeUserToken(tx, models.PasswordResetToken, tokenHash)
+		if err != nil {
+			return err
+		}
+		userID = token.UserID
+
+		if err := tx.Model(&models.User{}).Where("id = ?", token.UserID).Updates(map[string]any{
+			"password":            passwordHash,
+			"password_changed_at": time.Now(),
+			"token_version":       gorm.Expr("token_version + 1"),
+		}).Error; err != nil {
+			return err
+		}
+		return revokeUserSessions(tx, token.UserID)
+	})
+	return userID, err
+}
+
+// VerifyEmail consumes an email verification token and marks the user's
+// email as verified.
+func (r *UserTokenRepository) VerifyEmail(tokenHash string) (uint, error) {
+	var userID uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		token, err := consumeUserToken(tx, models.EmailVerificationToken, tokenHash)
+		if err != nil {
+			return err
+		}
+		userID = token.UserID
+
+		return tx.Model(&models.User{}).Where("id = ? AND email_verified_at IS NULL", token.UserID).
+			Update("email_verified_at", time.Now()).Error
+	})
+	return userID, err
+}
+
+// LastIssuedAt returns when the latest token of purpose was issued to the
+// user, or nil if none was.
+func (r *UserTokenRepository) LastIssuedAt(userID uint, purpose models.TokenPurpose) (*time.Time, error) {
+	var tokens []models.UserToken
+	if err := r.db.Where("user_id = ? AND purpose = ?", userID, purpose).
+		Order("created_at DESC").Limit(1).Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+	return &tokens[0].CreatedAt, nil
+}
+
+// GetTokenUser returns the user a valid token of purpose was issued to,
+// without using the token up.
+func (r *UserTokenRepository) GetTokenUser(purpose models.TokenPurpose, tokenHash string) (*models.User, error) {
+	var token models.UserToken
+	if err := r.db.Where("token_hash = ? AND purpose = ?", tokenHash, purpose).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidUserToken
+		}
+		return nil, err
+	}
+	if token.UsedAt != nil || time.Now().After(token.ExpiresAt) {
+		return nil, ErrInvalidUserToken
+	}
+
+	var user models.User
+	if err := r.db.First(&user, token.UserID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidUserToken
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// consumeUserToken marks a valid token as used and returns it.
+func consumeUserToken(tx *gorm.DB, purpose models.TokenPurpose, tokenHash string) (*models.UserToken, error) {
+	var token models.UserToken
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("token_hash = ? AND purpose = ?", tokenHash, purpose).
+		First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidUserToken
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if token.UsedAt != nil || now.After(token.ExpiresAt) {
+		return nil, ErrInvalidUserToken
+	}
+
+	token.UsedAt = &now
+	if err := tx.Model(&token).Update("used_at", now).Error; err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
@@ -0,0 +1,15 @@
+package form
+
+import "go-finance-tracker/pkg/money"
+
+type AccountInput struct {
+	Name           string       `json:"name" validate:"required,max=64"`
+	Type           string       `json:"type" validate:"required,oneof=CHECKING SAVINGS CREDIT_CARD CASH OTHER"`
+	Currency       string       `json:"currency" validate:"required,len=3"`
+	OpeningBalance money.Amount `json:"openingBalance"`
+	Archived       bool         `json:"archived"`
+}
+
+type AccountQueryInput struct {
+	IncludeArchived bool `form:"includeArchived"`
+}
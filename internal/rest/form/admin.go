@@ -0,0 +1,22 @@
+package form
+
+// UserQueryInput is bound from the query string of GET /v1/admin/users.
+type UserQueryInput struct {
+	Search  string `form:"q" validate:"max=64"`
+	Deleted bool   `form:"deleted"`
+	Cursor  string `form:"cursor"`
+	Limit   int    `form:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+type RoleInput struct {
+	Role string `json:"role" validate:"required"`
+}
+
+// AuditQueryInput is bound from the query string of GET /v1/admin/audit.
+type AuditQueryInput struct {
+	ActorID *uint  `form:"actorID"`
+	UserID  *uint  `form:"userID"`
+	Action  string `form:"action"`
+	Cursor  string `form:"cursor"`
+	Limit   int    `form:"limit" validate:"omitempty,min=1,max=100"`
+}
@@ -1,14 +1,70 @@
 package form
 
 type LoginInput struct {
-	Username string `json:"username" validate:"required"`
+	Username string `json:"username" validate:"required,max=35"`
 	Password string `json:"password" validate:"required"`
+	// Device is an optional name shown in the session list.
+	Device string `json:"device" validate:"max=64"`
+	// ReturnTokens also returns the tokens in the response body, for clients
+	// that send them in the Authorization header instead of cookies.
+	ReturnTokens bool `json:"returnTokens"`
+}
+
+// RefreshInput carries the refresh token for clients that do not use the
+// refresh token cookie.
+type RefreshInput struct {
+	RefreshToken string `json:"refreshToken"`
 }
 
 type RegisterInput struct {
 	Name     string `json:"name" validate:"required"`
 	Surname  string `json:"surname" validate:"required"`
 	Username string `json:"username" validate:"required"`
-	Email    string `json:"email"  validate:"required"`
+	Email    string `json:"email"  validate:"required,mailbox"`
+	Password string `json:"password" validate:"required"`
+}
+
+type ForgotPasswordInput struct {
+	Email string `json:"email" validate:"required,mailbox"`
+}
+
+type ResendVerificationInput struct {
+	Email string `json:"email" validate:"required,mailbox"`
+}
+
+type ResetPasswordInput struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required"`
+}
+
+type ChangePasswordInput struct {
+	CurrentPassword string `json:"currentPassword" validate:"required"`
+	NewPassword     string `json:"newPassword" validate:"required"`
+}
+
+// ProfileInput holds a partial profile update; nil fields are left unchanged.
+type ProfileInput struct {
+	Name         *string `json:"name" validate:"omitempty,min=1,max=35"`
+	Surname      *string `json:"surname" validate:"omitempty,min=1,max=35"`
+	Email        *string `json:"email" validate:"omitempty,mailbox"`
+	BaseCurrency *string `json:"baseCurrency" validate:"omitempty,len=3,alpha"`
+}
+
+// MFACodeInput carries a TOTP code from the authenticator app.
+type MFACodeInput struct {
+	Code string `json:"code" validate:"required,max=16"`
+}
+
+// MFALoginInput finishes a login for a user with two-factor authentication.
+// Code is either a TOTP code or one of the recovery codes.
+type MFALoginInput struct {
+	MFAToken     string `json:"mfaToken" validate:"required"`
+	Code         string `json:"code" validate:"required,max=16"`
+	Device       string `json:"device" validate:"max=64"`
+	ReturnTokens bool   `json:"returnTokens"`
+}
+
+type DisableMFAInput struct {
 	Password string `json:"password" validate:"required"`
+	Code     string `json:"code" validate:"required,max=16"`
 }
@@ -0,0 +1,18 @@
+package form
+
+import "go-finance-tracker/pkg/money"
+
+type BudgetInput struct {
+	CategoryID uint         `json:"categoryID" validate:"required"`
+	PeriodType string       `json:"periodType" validate:"required,oneof=WEEKLY MONTHLY YEARLY"`
+	Amount     money.Amount `json:"amount" validate:"gt=0"`
+	StartDate  string       `json:"startDate" validate:"required,datetime=2006-01-02"`
+	Rollover   bool         `json:"rollover"`
+}
+
+// BudgetStatusInput is bound from the query string of the budget status
+// endpoints. Periods counts the current period and the ones before it.
+type BudgetStatusInput struct {
+	Periods  int    `form:"periods" validate:"omitempty,min=1,max=24"`
+	Timezone string `form:"timezone"`
+}
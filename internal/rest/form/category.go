@@ -0,0 +1,10 @@
+package form
+
+type CategoryInput struct {
+	Name     string `json:"name" validate:"required,max=64"`
+	ParentID *uint  `json:"parentID"`
+}
+
+type CategoryMergeInput struct {
+	TargetID uint `json:"targetID" validate:"required"`
+}
@@ -0,0 +1,26 @@
+package form
+
+import "go-finance-tracker/pkg/money"
+
+// ExchangeRateInput is one rate of an import: one From buys Rate To on Date,
+// a day in the "2006-01-02" format.
+type ExchangeRateInput struct {
+	Date string     `json:"date" validate:"required"`
+	From string     `json:"from" validate:"required,len=3"`
+	To   string     `json:"to" validate:"required,len=3,nefield=From"`
+	Rate money.Rate `json:"rate" validate:"required"`
+}
+
+type ImportExchangeRatesInput struct {
+	Rates []ExchangeRateInput `json:"rates" validate:"required,min=1,max=1000,dive"`
+}
+
+// ExchangeRateQueryInput is bound from the query string of
+// GET /v1/exchange-rates. Since and Until are inclusive days.
+type ExchangeRateQueryInput struct {
+	From  string `form:"from" validate:"omitempty,len=3"`
+	To    string `form:"to" validate:"omitempty,len=3"`
+	Since string `form:"since"`
+	Until string `form:"until"`
+	Limit int    `form:"limit" validate:"omitempty,min=1,max=100"`
+}
@@ -1,8 +1,57 @@
 package form
 
+import (
+	"go-finance-tracker/pkg/money"
+	"time"
+)
+
+// FinanceRecordInput describes a record. Without an AccountID new records go
+// to the user's default account. Splits divide the amount between categories,
+// and the record is then listed under the first line's category: CategoryID
+// may be left out, and must be that category otherwise. The amount must not
+// be zero.
 type FinanceRecordInput struct {
-	Amount            float64 `json:"amount"`
-	TransactionTypeID uint    `json:"transactionTypeID"`
-	CategoryID        uint    `json:"categoryID"`
-	Note              string  `json:"note"`
+	AccountID         uint         `json:"accountID"`
+	Amount            money.Amount `json:"amount"`
+	TransactionTypeID uint         `json:"transactionTypeID"`
+	CategoryID        uint         `json:"categoryID"`
+	Note              string       `json:"note"`
+	Splits            []SplitInput `json:"splits" validate:"max=50,dive"`
+}
+
+// SplitInput is one line of a split record.
+type SplitInput struct {
+	CategoryID uint         `json:"categoryID" validate:"required"`
+	Amount     money.Amount `json:"amount"`
+	Note       string       `json:"note" validate:"max=255"`
+}
+
+// FinanceRecordPatchInput holds a partial update; nil fields are left
+// unchanged. An empty Splits list turns a split record back into a plain one;
+// new Splits without a CategoryID move the record to their first line's
+// category.
+type FinanceRecordPatchInput struct {
+	AccountID         *uint         `json:"accountID"`
+	Amount            *money.Amount `json:"amount"`
+	TransactionTypeID *uint         `json:"transactionTypeID"`
+	CategoryID        *uint         `json:"categoryID"`
+	Note              *string       `json:"note"`
+	Splits            *[]SplitInput `json:"splits" validate:"omitempty,max=50,dive"`
+}
+
+// FinanceQueryInput is bound from the query string of GET /v1/finance.
+// Dates are inclusive calendar days in the "2006-01-02" format.
+type FinanceQueryInput struct {
+	From              *time.Time `form:"from" time_format:"2006-01-02"`
+	To                *time.Time `form:"to" time_format:"2006-01-02"`
+	AccountID         *uint      `form:"accountID"`
+	CategoryID        *uint      `form:"categoryID"`
+	TransactionTypeID *uint      `form:"transactionTypeID"`
+	MinAmount         string     `form:"minAmount"`
+	MaxAmount         string     `form:"maxAmount"`
+	Note              string     `form:"note"`
+	Sort              string     `form:"sort" validate:"omitempty,oneof=date amount"`
+	Order             string     `form:"order" validate:"omitempty,oneof=asc desc"`
+	Cursor            string     `form:"cursor"`
+	Limit             int        `form:"limit" validate:"omitempty,min=1,max=100"`
 }
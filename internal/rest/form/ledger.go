@@ -0,0 +1,41 @@
+package form
+
+import (
+	"go-finance-tracker/pkg/money"
+	"time"
+)
+
+type LedgerAccountInput struct {
+	Name string `json:"name" validate:"required,max=128"`
+	Type string `json:"type" validate:"required,oneof=ASSET LIABILITY INCOME EXPENSE EQUITY"`
+}
+
+// PostingInput debits the ledger account when Amount is positive and
+// credits it when negative.
+type PostingInput struct {
+	LedgerAccountID uint         `json:"ledgerAccountID" validate:"required"`
+	Amount          money.Amount `json:"amount"`
+	Currency        string       `json:"currency" validate:"required,len=3"`
+}
+
+// JournalEntryInput describes a manual entry. Without a Date it is booked
+// now.
+type JournalEntryInput struct {
+	Date        *time.Time     `json:"date"`
+	Description string         `json:"description" validate:"max=255"`
+	Postings    []PostingInput `json:"postings" validate:"required,min=2,max=100,dive"`
+}
+
+type JournalQueryInput struct {
+	LedgerAccountID uint   `form:"ledgerAccountID"`
+	Cursor          string `form:"cursor"`
+	Limit           int    `form:"limit" validate:"omitempty,min=1,max=100"`
+}
+
+// LedgerReportInput is bound from the query string of the trial balance and
+// balance sheet. At is an inclusive day in the "2006-01-02" format,
+// interpreted in the Timezone; without it the report is as of now.
+type LedgerReportInput struct {
+	At       string `form:"at"`
+	Timezone string `form:"timezone"`
+}
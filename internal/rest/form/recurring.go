@@ -0,0 +1,25 @@
+package form
+
+import (
+	"go-finance-tracker/pkg/money"
+	"time"
+)
+
+type RecurringRuleInput struct {
+	AccountID         uint         `json:"accountID"`
+	Amount            money.Amount `json:"amount" validate:"gt=0"`
+	TransactionTypeID uint         `json:"transactionTypeID" validate:"required"`
+	CategoryID        uint         `json:"categoryID"`
+	Note              string       `json:"note"`
+	Frequency         string       `json:"frequency" validate:"required,oneof=DAILY WEEKLY MONTHLY YEARLY"`
+	Interval          int          `json:"interval" validate:"omitempty,min=1,max=1000"`
+	DayOfMonth        int          `json:"dayOfMonth" validate:"omitempty,min=1,max=31"`
+	Timezone          string       `json:"timezone"`
+	StartDate         time.Time    `json:"startDate" validate:"required"`
+	EndDate           *time.Time   `json:"endDate"`
+	Count             *int         `json:"count" validate:"omitempty,min=1"`
+}
+
+type RecurringPreviewInput struct {
+	Count int `form:"count" validate:"omitempty,min=1,max=100"`
+}
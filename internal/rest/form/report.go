@@ -0,0 +1,13 @@
+package form
+
+// SummaryQueryInput is bound from the query string of GET /v1/reports/summary.
+// Dates are inclusive calendar days in the "2006-01-02" format, interpreted in
+// the Timezone (an IANA name such as "Asia/Almaty"). Amounts are reported in
+// Currency, the user's base currency by default.
+type SummaryQueryInput struct {
+	From     string `form:"from"`
+	To       string `form:"to"`
+	GroupBy  string `form:"groupBy" validate:"omitempty,oneof=day week month year"`
+	Timezone string `form:"timezone"`
+	Currency string `form:"currency" validate:"omitempty,len=3,alpha"`
+}
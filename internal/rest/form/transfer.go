@@ -0,0 +1,10 @@
+package form
+
+import "go-finance-tracker/pkg/money"
+
+type TransferInput struct {
+	FromAccountID uint         `json:"fromAccountID" validate:"required"`
+	ToAccountID   uint         `json:"toAccountID" validate:"required,nefield=FromAccountID"`
+	Amount        money.Amount `json:"amount" validate:"gt=0"`
+	Note          string       `json:"note"`
+}
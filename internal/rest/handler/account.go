@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go-finance-tracker/internal/models"
+	"go-finance-tracker/internal/repository"
+	"go-finance-tracker/internal/rest/form"
+	"go-finance-tracker/pkg/logger"
+	"go-finance-tracker/pkg/money"
+	"net/http"
+)
+
+type AccountHandlers struct {
+	accountRepo repository.AccountRepo
+}
+
+func NewAccountHandlers(accountRepo repository.AccountRepo) *AccountHandlers {
+	return &AccountHandlers{accountRepo: accountRepo}
+}
+
+func (h *AccountHandlers) GetAllAccounts(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var queryForm form.AccountQueryInput
+	if err := ctx.ShouldBindQuery(&queryForm); err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	accounts, err := h.accountRepo.GetAll(userID, queryForm.IncludeArchived)
+	if err != nil {
+		respondAccountError(ctx, "Failed to fetch accounts:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Accounts fetched successfully",
+		Data:    accounts,
+	})
+}
+
+func (h *AccountHandlers) GetAccount(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	accountID, ok := getIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	account, err := h.accountRepo.GetByID(userID, accountID)
+	if err != nil {
+		respondAccountError(ctx, "Failed to fetch account:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Account fetched successfully",
+		Data:    account,
+	})
+}
+
+func (h *AccountHandlers) CreateAccount(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	account, ok := bindAccount(ctx, userID)
+	if !ok {
+		return
+	}
+
+	if err := h.accountRepo.Create(account); err != nil {
+		respondAccountError(ctx, "Failed to create account:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, &models.CustomResponse{
+		Status:  http.StatusCreated,
+		Message: "Account created successfully",
+		Data:    account,
+	})
+}
+
+// UpdateAccount replaces the account's details. Setting "archived" hides the
+// account from the list and stops new records from being booked to it.
+func (h *AccountHandlers) UpdateAccount(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	accountID, ok := getIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	account, ok := bindAccount(ctx, userID)
+	if !ok {
+		return
+	}
+	account.ID = accountID
+
+	if err := h.accountRepo.Update(account); err != nil {
+		respondAccountError(ctx, "Failed to update account:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Account updated successfully",
+	})
+}
+
+// DeleteAccount removes an account without history. Accounts with finance
+// records or recurring rules can only be archived.
+func (h *AccountHandlers) DeleteAccount(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	accountID, ok := getIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.accountRepo.Delete(userID, accountID); err != nil {
+		respondAccountError(ctx, "Failed to delete account:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Account deleted successfully",
+	})
+}
+
+func bindAccount(ctx *gin.Context, userID int) (*models.Account, bool) {
+	var accountForm form.AccountInput
+	if err := ctx.ShouldBindJSON(&accountForm); err != nil {
+		logger.GetLogger().Error("Invalid account request:", err)
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return nil, false
+	}
+	if err := validate(accountForm); err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return nil, false
+	}
+
+	currency, err := money.ParseCurrency(accountForm.Currency)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return nil, false
+	}
+
+	return &models.Account{
+		UserID:         uint(userID),
+		Name:           accountForm.Name,
+		Type:           models.AccountType(accountForm.Type),
+		Currency:       currency,
+		OpeningBalance: accountForm.OpeningBalance,
+		Archived:       accountForm.Archived,
+	}, true
+}
+
+// resolveAccount returns the ID of the account a record of userID should be
+// booked to, the user's default account when accountID is zero. Missing
+// and archived accounts are answered with 400.
+func resolveAccount(ctx *gin.Context, accountRepo repository.AccountRepo, userID int, accountID uint) (uint, bool) {
+	account, err := accountRepo.Resolve(userID, accountID)
+	if err != nil {
+		if errors.Is(err, repository.ErrAccountNotFound) || errors.Is(err, repository.ErrAccountArchived) {
+			ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+				Status: http.StatusBadRequest,
+				Error:  err.Error(),
+			})
+			return 0, false
+		}
+		logger.GetLogger().Error("Failed to fetch account:", err)
+		ctx.JSON(http.StatusInternalServerError, &models.CustomResponse{
+			Status: http.StatusInternalServerError,
+			Error:  err.Error(),
+		})
+		return 0, false
+	}
+	return account.ID, true
+}
+
+func respondAccountError(ctx *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repository.ErrAccountNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, repository.ErrAccountInUse),
+		errors.Is(err, repository.ErrAccountCurrencyLocked):
+		status = http.StatusConflict
+	default:
+		logger.GetLogger().Error(message, err)
+	}
+
+	ctx.JSON(status, &models.CustomResponse{
+		Status: status,
+		Error:  err.Error(),
+	})
+}
@@ -0,0 +1,515 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go-finance-tracker/internal/models"
+	"go-finance-tracker/internal/repository"
+	"go-finance-tracker/internal/rest/form"
+	"go-finance-tracker/pkg/logger"
+	"go-finance-tracker/pkg/utils"
+	"net/http"
+	"strconv"
+)
+
+var errSelfAdministration = errors.New("administrators cannot do this to their own account")
+
+type AdminHandlers struct {
+	userRepo    repository.UserRepo
+	roleRepo    repository.RoleRepo
+	auditRepo   repository.AuditRepo
+	balanceRepo repository.BalanceRepo
+	mfaRepo     repository.MFARepo
+	// auth sends the verification and password reset emails.
+	auth *AuthHandlers
+}
+
+func NewAdminHandlers(
+	userRepo repository.UserRepo,
+	roleRepo repository.RoleRepo,
+	auditRepo repository.AuditRepo,
+	balanceRepo repository.BalanceRepo,
+	mfaRepo repository.MFARepo,
+	authHandlers *AuthHandlers,
+) *AdminHandlers {
+	return &AdminHandlers{
+		userRepo:    userRepo,
+		roleRepo:    roleRepo,
+		auditRepo:   auditRepo,
+		balanceRepo: balanceRepo,
+		mfaRepo:     mfaRepo,
+		auth:        authHandlers,
+	}
+}
+
+func (h *AdminHandlers) GetUsers(ctx *gin.Context) {
+	var queryForm form.UserQueryInput
+	if err := ctx.ShouldBindQuery(&queryForm); err != nil {
+		logger.GetLogger().Error("Invalid user query:", err)
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+	if err := validate(queryForm); err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	page, err := h.userRepo.SearchUsers(repository.UserQuery{
+		Search:  queryForm.Search,
+		Deleted: queryForm.Deleted,
+		Cursor:  queryForm.Cursor,
+		Limit:   queryForm.Limit,
+	})
+	if err != nil {
+		respondAdminError(ctx, "Failed to fetch users:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Users fetched successfully",
+		Data:    page,
+	})
+}
+
+func (h *AdminHandlers) GetUser(ctx *gin.Context) {
+	userID, ok := getIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	user, err := h.userRepo.GetUserIncludingDeleted(userID)
+	if err != nil {
+		respondAdminError(ctx, "Failed to fetch user:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "User fetched successfully",
+		Data:    user,
+	})
+}
+
+// UpdateUser changes a user's name, surname, email or base currency. A new
+// email has to be verified again, and a new base currency recomputes the
+// user's balance in it.
+func (h *AdminHandlers) UpdateUser(ctx *gin.Context) {
+	actorID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	userID, ok := getIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	var profileForm form.ProfileInput
+	if err := ctx.ShouldBindJSON(&profileForm); err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+	if err := validate(profileForm); err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	user, err := h.userRepo.GetUserByID(userID)
+	if err != nil {
+		respondAdminError(ctx, "Failed to fetch user:", err)
+		return
+	}
+
+	changes := applyProfile(user, profileForm)
+	if _, ok := changes["email"]; ok {
+		if err := checkEmailAvailable(h.userRepo, user.Email, user.ID); err != nil {
+			respondAdminError(ctx, "Failed to check email:", err)
+			return
+		}
+	}
+
+	if len(changes) > 0 {
+		entry := auditEntry(ctx, actorID, models.AuditUserUpdated, user.ID, changes)
+		if err := h.userRepo.UpdateUser(user, entry); err != nil {
+			respondAdminError(ctx, "Failed to update user:", err)
+			return
+		}
+
+		if !user.IsEmailVerified() {
+			if err := h.auth.sendEmailVerification(user); err != nil {
+				logger.GetLogger().Error("Failed to issue verification token:", err)
+			}
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "User updated successfully",
+		Data:    user,
+	})
+}
+
+// AssignRole grants a role. Like every role change it reaches the user's
+// tokens on their next refresh.
+func (h *AdminHandlers) AssignRole(ctx *gin.Context) {
+	actorID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	userID, ok := getIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	var roleForm form.RoleInput
+	if err := ctx.ShouldBindJSON(&roleForm); err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+	if err := validate(roleForm); err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	h.changeRole(ctx, actorID, userID, roleForm.Role, true)
+}
+
+func (h *AdminHandlers) RevokeRole(ctx *gin.Context) {
+	actorID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	userID, ok := getIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	roleName := ctx.Param("role")
+	if uint(actorID) == userID && roleName == models.RoleAdmin {
+		respondAdminError(ctx, "", errSelfAdministration)
+		return
+	}
+
+	h.changeRole(ctx, actorID, userID, roleName, false)
+}
+
+func (h *AdminHandlers) changeRole(ctx *gin.Context, actorID int, userID uint, roleName string, assign bool) {
+	user, err := h.userRepo.GetUserByID(userID)
+	if err != nil {
+		respondAdminError(ctx, "Failed to fetch user:", err)
+		return
+	}
+	role, err := h.roleRepo.GetByName(roleName)
+	if err != nil {
+		respondAdminError(ctx, "Failed to fetch role:", err)
+		return
+	}
+
+	action := models.AuditRoleAssigned
+	change := h.roleRepo.Assign
+	if !assign {
+		action = models.AuditRoleRevoked
+		change = h.roleRepo.Revoke
+	}
+	entry := auditEntry(ctx, actorID, action, user.ID, models.AuditDetails{"role": role.Name})
+	if err := change(user.ID, role, entry); err != nil {
+		respondAdminError(ctx, "Failed to change role:", err)
+		return
+	}
+
+	user, err = h.userRepo.GetUserByID(userID)
+	if err != nil {
+		respondAdminError(ctx, "Failed to fetch user:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "User roles updated successfully",
+		Data:    user,
+	})
+}
+
+// LockUser blocks logins and revokes the user's sessions until UnlockUser.
+func (h *AdminHandlers) LockUser(ctx *gin.Context) {
+	h.setLocked(ctx, true)
+}
+
+func (h *AdminHandlers) UnlockUser(ctx *gin.Context) {
+	h.setLocked(ctx, false)
+}
+
+func (h *AdminHandlers) setLocked(ctx *gin.Context, locked bool) {
+	actorID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	userID, ok := getIDParam(ctx)
+	if !ok {
+		return
+	}
+	if locked && uint(actorID) == userID {
+		respondAdminError(ctx, "", errSelfAdministration)
+		return
+	}
+
+	action, message := models.AuditUserLocked, "User locked successfully"
+	if !locked {
+		action, message = models.AuditUserUnlocked, "User unlocked successfully"
+	}
+	if err := h.userRepo.SetLocked(userID, locked, auditEntry(ctx, actorID, action, userID, nil)); err != nil {
+		respondAdminError(ctx, "Failed to change lock state:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: message,
+	})
+}
+
+// DeleteUser soft-deletes the user; RestoreUser brings them back.
+func (h *AdminHandlers) DeleteUser(ctx *gin.Context) {
+	actorID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	userID, ok := getIDParam(ctx)
+	if !ok {
+		return
+	}
+	if uint(actorID) == userID {
+		respondAdminError(ctx, "", errSelfAdministration)
+		return
+	}
+
+	if err := h.userRepo.DeleteUser(userID, auditEntry(ctx, actorID, models.AuditUserDeleted, userID, nil)); err != nil {
+		respondAdminError(ctx, "Failed to delete user:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "User deleted successfully",
+	})
+}
+
+func (h *AdminHandlers) RestoreUser(ctx *gin.Context) {
+	actorID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	userID, ok := getIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.userRepo.RestoreUser(userID, auditEntry(ctx, actorID, models.AuditUserRestored, userID, nil)); err != nil {
+		respondAdminError(ctx, "Failed to restore user:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "User restored successfully",
+	})
+}
+
+// ForcePasswordReset replaces the user's password with a random one, signs
+// them out everywhere and emails them a password reset link.
+func (h *AdminHandlers) ForcePasswordReset(ctx *gin.Context) {
+	actorID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	userID, ok := getIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	user, err := h.userRepo.GetUserByID(userID)
+	if err != nil {
+		respondAdminError(ctx, "Failed to fetch user:", err)
+		return
+	}
+
+	randomPassword, _, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		respondAdminError(ctx, "Failed to generate password:", err)
+		return
+	}
+	hashedPassword, err := utils.HashPassword(randomPassword)
+	if err != nil {
+		respondAdminError(ctx, "Unable to hash the password:", err)
+		return
+	}
+
+	entry := auditEntry(ctx, actorID, models.AuditPasswordResetForced, user.ID, nil)
+	if err := h.userRepo.ResetCredentials(user.ID, hashedPassword, entry); err != nil {
+		respondAdminError(ctx, "Failed to revoke credentials:", err)
+		return
+	}
+
+	if err := h.auth.sendPasswordReset(user); err != nil {
+		respondAdminError(ctx, "Failed to issue password reset token:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Password reset email sent",
+	})
+}
+
+// ResetMFA turns off two-factor authentication for a user who lost their
+// authenticator and recovery codes. They can log in with their password
+// and enroll again.
+func (h *AdminHandlers) ResetMFA(ctx *gin.Context) {
+	actorID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	userID, ok := getIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	user, err := h.userRepo.GetUserByID(userID)
+	if err != nil {
+		respondAdminError(ctx, "Failed to fetch user:", err)
+		return
+	}
+	if !user.IsMFAEnabled() && user.TOTPSecret == "" {
+		respondAdminError(ctx, "", repository.ErrMFANotEnabled)
+		return
+	}
+
+	if err := h.mfaRepo.Disable(user.ID, auditEntry(ctx, actorID, models.AuditMFAReset, user.ID, nil)); err != nil {
+		respondAdminError(ctx, "Failed to reset two-factor authentication:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Two-factor authentication reset successfully",
+	})
+}
+
+func (h *AdminHandlers) GetAuditLog(ctx *gin.Context) {
+	var queryForm form.AuditQueryInput
+	if err := ctx.ShouldBindQuery(&queryForm); err != nil {
+		logger.GetLogger().Error("Invalid audit query:", err)
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+	if err := validate(queryForm); err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	page, err := h.auditRepo.Find(repository.AuditQuery{
+		ActorID:      queryForm.ActorID,
+		TargetUserID: queryForm.UserID,
+		Action:       models.AuditAction(queryForm.Action),
+		Cursor:       queryForm.Cursor,
+		Limit:        queryForm.Limit,
+	})
+	if err != nil {
+		respondAdminError(ctx, "Failed to fetch audit log:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Audit log fetched successfully",
+		Data:    page,
+	})
+}
+
+// RecomputeBalances reports users whose stored balance has drifted from
+// their finance history, and corrects them when "fix=true" is given.
+func (h *AdminHandlers) RecomputeBalances(ctx *gin.Context) {
+	fix, err := strconv.ParseBool(ctx.DefaultQuery("fix", "false"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  "fix must be a boolean",
+		})
+		return
+	}
+
+	drifts, err := h.balanceRepo.Recompute(fix)
+	if err != nil {
+		logger.GetLogger().Error("Failed to recompute balances:", err)
+		ctx.JSON(http.StatusInternalServerError, &models.CustomResponse{
+			Status: http.StatusInternalServerError,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Balances recomputed successfully",
+		Data:    drifts,
+	})
+}
+
+// auditEntry describes an action for the repository, which records it in
+// the action's own transaction: if the entry cannot be written, the action
+// fails too.
+func auditEntry(ctx *gin.Context, actorID int, action models.AuditAction, targetUserID uint, details models.AuditDetails) *models.AuditLog {
+	return &models.AuditLog{
+		ActorID:      uint(actorID),
+		Action:       action,
+		TargetUserID: targetUserID,
+		IP:           ctx.ClientIP(),
+		Details:      details,
+	}
+}
+
+func respondAdminError(ctx *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repository.ErrUserNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, errEmailTaken):
+		status = http.StatusConflict
+	case errors.Is(err, repository.ErrRoleNotFound),
+		errors.Is(err, repository.ErrInvalidCursor),
+		errors.Is(err, repository.ErrMFANotEnabled),
+		errors.Is(err, errSelfAdministration):
+		status = http.StatusBadRequest
+	default:
+		logger.GetLogger().Error(message, err)
+	}
+
+	ctx.JSON(status, &models.CustomResponse{
+		Status: status,
+		Error:  err.Error(),
+	})
+}
@@ -3,9 +3,11 @@ package handler
 import (
 	"errors"
 	"github.com/gin-gonic/gin"
+	"go-finance-tracker/internal/config"
 	"go-finance-tracker/internal/models"
 	"go-finance-tracker/internal/repository"
 	"go-finance-tracker/internal/rest/form"
+	"go-finance-tracker/pkg/email"
 	"go-finance-tracker/pkg/logger"
 	"go-finance-tracker/pkg/utils"
 	"net/http"
@@ -13,18 +15,83 @@ import (
 	"time"
 )
 
+var (
+	errTokenRevoked   = errors.New("token was issued before the last password change")
+	errSessionRevoked = errors.New("session is revoked or expired")
+)
+
+// dummyPasswordHash is checked against for unknown usernames so that they
+// take as long as wrong passwords. Its cost must match utils.HashPassword.
+const dummyPasswordHash = "$2a$14$CDnCNjfxmbN1G9a2HG.Fku0qJ33SwlJgXizPfH/cRhws2JGthWAaC"
+
 type AuthHandlers struct {
-	UserRepo repository.UserRepo
-	RoleRepo repository.RoleRepo
+	UserRepo         repository.UserRepo
+	RoleRepo         repository.RoleRepo
+	UserTokenRepo    repository.UserTokenRepo
+	SessionRepo      repository.SessionRepo
+	LoginAttemptRepo repository.LoginAttemptRepo
+	MFARepo          repository.MFARepo
+	EmailSender      email.Sender
+	Config           config.Auth
 }
 
-func NewAuthHandler(userRepo repository.UserRepo, roleRepo repository.RoleRepo) *AuthHandlers {
+func NewAuthHandler(
+	userRepo repository.UserRepo,
+	roleRepo repository.RoleRepo,
+	userTokenRepo repository.UserTokenRepo,
+	sessionRepo repository.SessionRepo,
+	loginAttemptRepo repository.LoginAttemptRepo,
+	mfaRepo repository.MFARepo,
+	emailSender email.Sender,
+	authConfig config.Auth,
+) *AuthHandlers {
 	return &AuthHandlers{
-		UserRepo: userRepo,
-		RoleRepo: roleRepo,
+		UserRepo:         userRepo,
+		RoleRepo:         roleRepo,
+		UserTokenRepo:    userTokenRepo,
+		SessionRepo:      sessionRepo,
+		LoginAttemptRepo: loginAttemptRepo,
+		MFARepo:          mfaRepo,
+		EmailSender:      emailSender,
+		Config:           authConfig,
 	}
 }
 
+// ValidateToken rejects tokens whose session was revoked and tokens issued
+// before the user's last password change, which carry an older token
+// version. It is registered with
+// middleware.SetTokenValidator.
+func (h *AuthHandlers) ValidateToken(claims *models.Claims) error {
+	id, err := strconv.Atoi(claims.Id)
+	if err != nil {
+		return err
+	}
+
+	if claims.SessionID == 0 {
+		return errSessionRevoked
+	}
+	session, err := h.SessionRepo.GetByID(claims.SessionID)
+	if err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			return errSessionRevoked
+		}
+		return err
+	}
+	if session.UserID != uint(id) || !session.IsActive(time.Now()) {
+		return errSessionRevoked
+	}
+
+	user, err := h.UserRepo.GetUserByID(uint(id))
+	if err != nil {
+		return err
+	}
+
+	if claims.TokenVersion != user.TokenVersion {
+		return errTokenRevoked
+	}
+	return nil
+}
+
 func (h *AuthHandlers) Register(ctx *gin.Context) {
 	var registerForm form.RegisterInput
 
@@ -46,6 +113,14 @@ func (h *AuthHandlers) Register(ctx *gin.Context) {
 		return
 	}
 
+	if err := utils.CheckPasswordPolicy(registerForm.Password, registerForm.Username, registerForm.Email); err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+
 	_, err := h.UserRepo.GetUserByUsername(registerForm.Username)
 	if err == nil {
 		logger.GetLogger().Error("Account already registered for username:", registerForm.Username)
@@ -56,6 +131,16 @@ func (h *AuthHandlers) Register(ctx *gin.Context) {
 		return
 	}
 
+	_, err = h.UserRepo.GetUserByEmail(registerForm.Email)
+	if err == nil {
+		logger.GetLogger().Error("Account already registered for email:", registerForm.Email)
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status:  http.StatusBadRequest,
+			Message: "The account is already registered",
+		})
+		return
+	}
+
 	var user models.User
 	user.Name = registerForm.Name
 	user.Surname = registerForm.Surname
@@ -63,7 +148,7 @@ func (h *AuthHandlers) Register(ctx *gin.Context) {
 	user.Email = registerForm.Email
 	user.TotalMoney = 0
 
-	role, err := h.RoleRepo.GetByName("USER")
+	role, err := h.RoleRepo.GetByName(models.RoleUser)
 	if err != nil {
 		logger.GetLogger().Error("Role not found!")
 		ctx.JSON(http.StatusInternalServerError, &models.CustomResponse{
@@ -94,9 +179,21 @@ func (h *AuthHandlers) Register(ctx *gin.Context) {
 		return
 	}
 
-	signedToken, err := utils.CreateToken(strconv.Itoa(int(user.ID)), user.Username)
-	if err != nil {
-		logger.GetLogger().Error("Failed to generate jwt token:", err)
+	if err := h.sendEmailVerification(&user); err != nil {
+		// The user can ask for a new link, so registration still succeeds.
+		logger.GetLogger().Error("Failed to issue verification token:", err)
+	}
+
+	if h.Config.RequireVerifiedEmail {
+		ctx.JSON(http.StatusOK, &models.CustomResponse{
+			Status:  http.StatusOK,
+			Message: "User registered successfully, please verify your email",
+		})
+		return
+	}
+
+	if _, err := h.startSession(ctx, &user, ""); err != nil {
+		logger.GetLogger().Error("Failed to start session:", err)
 		ctx.JSON(http.StatusInternalServerError, &models.CustomResponse{
 			Status: http.StatusInternalServerError,
 			Error:  err.Error(),
@@ -104,15 +201,6 @@ func (h *AuthHandlers) Register(ctx *gin.Context) {
 		return
 	}
 
-	cookie := http.Cookie{
-		Name:     "jwt",
-		Value:    signedToken,
-		Path:     "/",
-		Expires:  time.Now().Add(time.Hour * 24),
-		HttpOnly: true,
-	}
-	http.SetCookie(ctx.Writer, &cookie)
-
 	ctx.JSON(http.StatusOK, &models.CustomResponse{
 		Status:  http.StatusOK,
 		Message: "User registered successfully",
@@ -131,32 +219,88 @@ func (h *AuthHandlers) Login(ctx *gin.Context) {
 		return
 	}
 
+	if err := validate(loginForm); err != nil {
+		logger.GetLogger().Error("Invalid login request:", err)
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	ip := ctx.ClientIP()
+	if !h.checkLoginIP(ctx, ip) {
+		return
+	}
+
+	attempt := &models.LoginAttempt{Username: loginForm.Username, IP: ip}
 	user, err := h.UserRepo.GetUserByUsername(loginForm.Username)
 	if err != nil {
-		if errors.Is(err, repository.ErrUserNotFound) {
-			ctx.JSON(http.StatusNotFound, &models.CustomResponse{
-				Status: http.StatusNotFound,
+		if !errors.Is(err, repository.ErrUserNotFound) {
+			ctx.JSON(http.StatusInternalServerError, &models.CustomResponse{
+				Status: http.StatusInternalServerError,
 				Error:  err.Error(),
 			})
+			return
 		}
-		ctx.JSON(http.StatusInternalServerError, &models.CustomResponse{
-			Status: http.StatusInternalServerError,
-			Error:  err.Error(),
-		})
+		// Unknown usernames get the same answer as wrong passwords, after
+		// the same bcrypt work, so that logins cannot be used to probe for
+		// accounts.
+		utils.CheckPasswordHash(loginForm.Password, dummyPasswordHash)
+		logger.GetLogger().Error("Bad credentials for username:", loginForm.Username)
+		h.recordLoginFailure(attempt, nil)
+		respondBadCredentials(ctx)
+		return
+	}
+	attempt.UserID = &user.ID
+
+	if !h.checkLoginThrottle(ctx, user) {
+		return
 	}
 
 	if !utils.CheckPasswordHash(loginForm.Password, user.Password) {
 		logger.GetLogger().Error("Bad credentials for username:", user.Username)
-		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
-			Status:  http.StatusBadRequest,
-			Message: "Bad credentials",
+		h.recordLoginFailure(attempt, user)
+		respondBadCredentials(ctx)
+		return
+	}
+
+	if user.IsLocked() {
+		logger.GetLogger().Error("Login attempt on locked account:", user.Username)
+		ctx.JSON(http.StatusForbidden, &models.CustomResponse{
+			Status:  http.StatusForbidden,
+			Message: "Account is locked",
 		})
 		return
 	}
 
-	signedToken, err := utils.CreateToken(strconv.Itoa(int(user.ID)), user.Username)
+	if h.Config.RequireVerifiedEmail && !user.IsEmailVerified() {
+		ctx.JSON(http.StatusForbidden, &models.CustomResponse{
+			Status:  http.StatusForbidden,
+			Message: "Email address is not verified",
+		})
+		return
+	}
+
+	// With two-factor authentication the attempt only succeeds once the
+	// code is checked, so failed codes count towards the lockout as well.
+	if user.IsMFAEnabled() {
+		h.requireMFA(ctx, user)
+		return
+	}
+
+	h.completeLogin(ctx, attempt, user, loginForm.Device, loginForm.ReturnTokens)
+}
+
+// completeLogin records a successful login and starts a session for user.
+func (h *AuthHandlers) completeLogin(ctx *gin.Context, attempt *models.LoginAttempt, user *models.User, device string, returnTokens bool) {
+	if err := h.LoginAttemptRepo.RecordSuccess(attempt); err != nil {
+		logger.GetLogger().Error("Failed to record login attempt:", err)
+	}
+
+	tokens, err := h.startSession(ctx, user, device)
 	if err != nil {
-		logger.GetLogger().Error("Failed to generate jwt token:", err)
+		logger.GetLogger().Error("Failed to start session:", err)
 		ctx.JSON(http.StatusInternalServerError, &models.CustomResponse{
 			Status: http.StatusInternalServerError,
 			Error:  err.Error(),
@@ -164,32 +308,34 @@ func (h *AuthHandlers) Login(ctx *gin.Context) {
 		return
 	}
 
-	cookie := http.Cookie{
-		Name:     "jwt",
-		Value:    signedToken,
-		Path:     "/",
-		Expires:  time.Now().Add(time.Hour * 24),
-		HttpOnly: true,
-	}
-	http.SetCookie(ctx.Writer, &cookie)
-
-	ctx.JSON(http.StatusOK, &models.CustomResponse{
+	response := &models.CustomResponse{
 		Status:  http.StatusOK,
 		Message: "User login successful",
-	})
+	}
+	if returnTokens {
+		response.Data = tokens
+	}
+	ctx.JSON(http.StatusOK, response)
 }
 
+// Logout revokes the current session and clears the token cookies.
 func (h *AuthHandlers) Logout(ctx *gin.Context) {
 	logger.GetLogger().Info("User logout")
 
-	cookie := http.Cookie{
-		Name:     "jwt",
-		Value:    "",
-		Path:     "/",
-		Expires:  time.Now().Add(-time.Hour),
-		HttpOnly: true,
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	if err := h.SessionRepo.Revoke(userID, ctx.GetUint("sessionID")); err != nil &&
+		!errors.Is(err, repository.ErrSessionNotFound) {
+		logger.GetLogger().Error("Failed to revoke session:", err)
+		ctx.JSON(http.StatusInternalServerError, &models.CustomResponse{
+			Status: http.StatusInternalServerError,
+			Error:  err.Error(),
+		})
+		return
 	}
-	http.SetCookie(ctx.Writer, &cookie)
+	h.clearAuthCookies(ctx)
 
 	ctx.JSON(http.StatusOK, &models.CustomResponse{
 		Status:  http.StatusOK,
@@ -0,0 +1,296 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go-finance-tracker/internal/models"
+	"go-finance-tracker/internal/repository"
+	"go-finance-tracker/internal/rest/form"
+	"go-finance-tracker/pkg/logger"
+	"net/http"
+	"time"
+)
+
+type BudgetHandlers struct {
+	budgetRepo   repository.BudgetRepo
+	categoryRepo repository.CategoryRepo
+}
+
+func NewBudgetHandlers(budgetRepo repository.BudgetRepo, categoryRepo repository.CategoryRepo) *BudgetHandlers {
+	return &BudgetHandlers{
+		budgetRepo:   budgetRepo,
+		categoryRepo: categoryRepo,
+	}
+}
+
+func (h *BudgetHandlers) GetAllBudgets(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	budgets, err := h.budgetRepo.GetAll(userID)
+	if err != nil {
+		respondBudgetError(ctx, "Failed to fetch budgets:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Budgets fetched successfully",
+		Data:    budgets,
+	})
+}
+
+func (h *BudgetHandlers) GetBudget(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	budgetID, ok := getIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	budget, err := h.budgetRepo.GetByID(userID, budgetID)
+	if err != nil {
+		respondBudgetError(ctx, "Failed to fetch budget:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Budget fetched successfully",
+		Data:    budget,
+	})
+}
+
+func (h *BudgetHandlers) CreateBudget(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	budget, ok := h.bindBudget(ctx, userID)
+	if !ok {
+		return
+	}
+
+	if err := h.budgetRepo.Create(budget); err != nil {
+		respondBudgetError(ctx, "Failed to create budget:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, &models.CustomResponse{
+		Status:  http.StatusCreated,
+		Message: "Budget created successfully",
+		Data:    budget,
+	})
+}
+
+func (h *BudgetHandlers) UpdateBudget(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	budgetID, ok := getIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	budget, ok := h.bindBudget(ctx, userID)
+	if !ok {
+		return
+	}
+	budget.ID = budgetID
+
+	if err := h.budgetRepo.Update(budget); err != nil {
+		respondBudgetError(ctx, "Failed to update budget:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Budget updated successfully",
+	})
+}
+
+func (h *BudgetHandlers) DeleteBudget(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	budgetID, ok := getIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.budgetRepo.Delete(userID, budgetID); err != nil {
+		respondBudgetError(ctx, "Failed to delete budget:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Budget deleted successfully",
+	})
+}
+
+// GetAllBudgetStatuses reports spent and remaining amounts of every budget of
+// the user for the current and past periods.
+func (h *BudgetHandlers) GetAllBudgetStatuses(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	periods, loc, ok := bindBudgetStatusQuery(ctx)
+	if !ok {
+		return
+	}
+
+	budgets, err := h.budgetRepo.GetAll(userID)
+	if err != nil {
+		respondBudgetError(ctx, "Failed to fetch budgets:", err)
+		return
+	}
+
+	now := time.Now()
+	statuses := make([]repository.BudgetStatus, 0, len(budgets))
+	for i := range budgets {
+		status, err := h.budgetRepo.Status(&budgets[i], now, periods, loc)
+		if err != nil {
+			respondBudgetError(ctx, "Failed to compute budget status:", err)
+			return
+		}
+		statuses = append(statuses, *status)
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Budget statuses fetched successfully",
+		Data:    statuses,
+	})
+}
+
+func (h *BudgetHandlers) GetBudgetStatus(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	budgetID, ok := getIDParam(ctx)
+	if !ok {
+		return
+	}
+	periods, loc, ok := bindBudgetStatusQuery(ctx)
+	if !ok {
+		return
+	}
+
+	budget, err := h.budgetRepo.GetByID(userID, budgetID)
+	if err != nil {
+		respondBudgetError(ctx, "Failed to fetch budget:", err)
+		return
+	}
+
+	status, err := h.budgetRepo.Status(budget, time.Now(), periods, loc)
+	if err != nil {
+		respondBudgetError(ctx, "Failed to compute budget status:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Budget status fetched successfully",
+		Data:    status,
+	})
+}
+
+// bindBudget reads a BudgetInput and checks that its category is visible to
+// the user.
+func (h *BudgetHandlers) bindBudget(ctx *gin.Context, userID int) (*models.Budget, bool) {
+	var budgetForm form.BudgetInput
+	if err := ctx.ShouldBindJSON(&budgetForm); err != nil {
+		logger.GetLogger().Error("Invalid budget request:", err)
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return nil, false
+	}
+	if err := validate(budgetForm); err != nil {
+		logger.GetLogger().Error("Invalid budget request:", err)
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return nil, false
+	}
+
+	if _, err := h.categoryRepo.GetVisible(userID, budgetForm.CategoryID); err != nil {
+		respondBudgetError(ctx, "Failed to fetch category:", err)
+		return nil, false
+	}
+
+	startDate, _ := time.Parse(dateLayout, budgetForm.StartDate)
+	return &models.Budget{
+		UserID:     uint(userID),
+		CategoryID: budgetForm.CategoryID,
+		PeriodType: models.BudgetPeriodType(budgetForm.PeriodType),
+		Amount:     budgetForm.Amount,
+		StartDate:  startDate,
+		Rollover:   budgetForm.Rollover,
+	}, true
+}
+
+func bindBudgetStatusQuery(ctx *gin.Context) (int, *time.Location, bool) {
+	var statusForm form.BudgetStatusInput
+	if err := ctx.ShouldBindQuery(&statusForm); err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return 0, nil, false
+	}
+	if err := validate(statusForm); err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return 0, nil, false
+	}
+
+	loc := time.UTC
+	if statusForm.Timezone != "" {
+		var err error
+		if loc, err = loadLocation(statusForm.Timezone); err != nil {
+			ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+				Status: http.StatusBadRequest,
+				Error:  err.Error(),
+			})
+			return 0, nil, false
+		}
+	}
+
+	periods := statusForm.Periods
+	if periods == 0 {
+		periods = 1
+	}
+	return periods, loc, true
+}
+
+func respondBudgetError(ctx *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repository.ErrBudgetNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, repository.ErrCategoryNotFound):
+		status = http.StatusBadRequest
+	default:
+		logger.GetLogger().Error(message, err)
+	}
+
+	ctx.JSON(status, &models.CustomResponse{
+		Status: status,
+		Error:  err.Error(),
+	})
+}
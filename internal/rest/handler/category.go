@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go-finance-tracker/internal/models"
+	"go-finance-tracker/internal/repository"
+	"go-finance-tracker/internal/rest/form"
+	"go-finance-tracker/pkg/logger"
+	"net/http"
+)
+
+type CategoryHandlers struct {
+	categoryRepo repository.CategoryRepo
+}
+
+func NewCategoryHandlers(categoryRepo repository.CategoryRepo) *CategoryHandlers {
+	return &CategoryHandlers{categoryRepo: categoryRepo}
+}
+
+func (h *CategoryHandlers) GetAllCategories(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	categories, err := h.categoryRepo.GetAllVisible(userID)
+	if err != nil {
+		respondCategoryError(ctx, "Failed to fetch categories:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Categories fetched successfully",
+		Data:    categories,
+	})
+}
+
+func (h *CategoryHandlers) GetCategory(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	categoryID, ok := getIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	category, err := h.categoryRepo.GetVisible(userID, categoryID)
+	if err != nil {
+		respondCategoryError(ctx, "Failed to fetch category:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Category fetched successfully",
+		Data:    category,
+	})
+}
+
+func (h *CategoryHandlers) CreateCategory(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var categoryForm form.CategoryInput
+	if !bindCategoryForm(ctx, &categoryForm) {
+		return
+	}
+
+	owner := uint(userID)
+	category := models.Category{
+		Name:     categoryForm.Name,
+		UserID:   &owner,
+		ParentID: categoryForm.ParentID,
+	}
+
+	if err := h.categoryRepo.Create(&category); err != nil {
+		respondCategoryError(ctx, "Failed to create category:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, &models.CustomResponse{
+		Status:  http.StatusCreated,
+		Message: "Category created successfully",
+		Data:    category,
+	})
+}
+
+func (h *CategoryHandlers) UpdateCategory(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	categoryID, ok := getIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	var categoryForm form.CategoryInput
+	if !bindCategoryForm(ctx, &categoryForm) {
+		return
+	}
+
+	owner := uint(userID)
+	category := models.Category{
+		Name:     categoryForm.Name,
+		UserID:   &owner,
+		ParentID: categoryForm.ParentID,
+	}
+	category.ID = categoryID
+
+	if err := h.categoryRepo.Update(&category); err != nil {
+		respondCategoryError(ctx, "Failed to update category:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Category updated successfully",
+	})
+}
+
+func (h *CategoryHandlers) DeleteCategory(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	categoryID, ok := getIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.categoryRepo.Delete(userID, categoryID); err != nil {
+		respondCategoryError(ctx, "Failed to delete category:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Category deleted successfully",
+	})
+}
+
+// MergeCategory reassigns the records of a category to another one and
+// deletes it.
+func (h *CategoryHandlers) MergeCategory(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	categoryID, ok := getIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	var mergeForm form.CategoryMergeInput
+	if err := ctx.ShouldBindJSON(&mergeForm); err != nil {
+		logger.GetLogger().Error("Invalid category merge request:", err)
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+	if err := validate(mergeForm); err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	if err := h.categoryRepo.Merge(userID, categoryID, mergeForm.TargetID); err != nil {
+		respondCategoryError(ctx, "Failed to merge category:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Category merged successfully",
+	})
+}
+
+func bindCategoryForm(ctx *gin.Context, categoryForm *form.CategoryInput) bool {
+	if err := ctx.ShouldBindJSON(categoryForm); err != nil {
+		logger.GetLogger().Error("Invalid category request:", err)
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return false
+	}
+	if err := validate(categoryForm); err != nil {
+		logger.GetLogger().Error("Invalid category request:", err)
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+func respondCategoryError(ctx *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repository.ErrCategoryNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, repository.ErrCategoryReadOnly):
+		status = http.StatusForbidden
+	case errors.Is(err, repository.ErrCategoryExists),
+		errors.Is(err, repository.ErrCategoryInUse):
+		status = http.StatusConflict
+	case errors.Is(err, repository.ErrCategoryCycle),
+		errors.Is(err, repository.ErrCategoryMergeSelf):
+		status = http.StatusBadRequest
+	default:
+		logger.GetLogger().Error(message, err)
+	}
+
+	ctx.JSON(status, &models.CustomResponse{
+		Status: status,
+		Error:  err.Error(),
+	})
+}
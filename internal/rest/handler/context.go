@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"go-finance-tracker/internal/models"
+	"go-finance-tracker/pkg/logger"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// getUserID reads the authenticated user ID set by RequireAuthMiddleware.
+// It writes the error response itself and returns false when the ID is missing.
+func getUserID(ctx *gin.Context) (int, bool) {
+	userIdStr, exists := ctx.Get("id")
+	if !exists {
+		logger.GetLogger().Error("User not authenticated")
+		ctx.JSON(http.StatusUnauthorized, &models.CustomResponse{
+			Status:  http.StatusUnauthorized,
+			Message: "User not authenticated",
+		})
+		return 0, false
+	}
+
+	id, err := strconv.Atoi(userIdStr.(string))
+	if err != nil {
+		logger.GetLogger().Error("Error while retrieving user ID:", err)
+		ctx.JSON(http.StatusInternalServerError, &models.CustomResponse{
+			Status: http.StatusInternalServerError,
+			Error:  "Error while retrieving user ID",
+		})
+		return 0, false
+	}
+
+	return id, true
+}
+
+// getIDParam parses the ":id" path parameter.
+func getIDParam(ctx *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil || id == 0 {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  "Invalid id",
+		})
+		return 0, false
+	}
+
+	return uint(id), true
+}
+
+// setRetryAfter tells the client how many whole seconds to wait.
+func setRetryAfter(ctx *gin.Context, wait time.Duration) {
+	ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go-finance-tracker/internal/exchange"
+	"go-finance-tracker/internal/models"
+	"go-finance-tracker/internal/repository"
+	"go-finance-tracker/internal/rest/form"
+	"go-finance-tracker/pkg/logger"
+	"go-finance-tracker/pkg/money"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type ExchangeRateHandlers struct {
+	exchangeRateRepo repository.ExchangeRateRepo
+}
+
+func NewExchangeRateHandlers(exchangeRateRepo repository.ExchangeRateRepo) *ExchangeRateHandlers {
+	return &ExchangeRateHandlers{exchangeRateRepo: exchangeRateRepo}
+}
+
+// GetExchangeRates lists the stored rates, newest first.
+func (h *ExchangeRateHandlers) GetExchangeRates(ctx *gin.Context) {
+	var queryForm form.ExchangeRateQueryInput
+	if err := ctx.ShouldBindQuery(&queryForm); err != nil {
+		logger.GetLogger().Error("Invalid exchange rate query:", err)
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+	if err := validate(queryForm); err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	query, err := buildExchangeRateQuery(queryForm)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	rates, err := h.exchangeRateRepo.Find(query)
+	if err != nil {
+		respondExchangeRateError(ctx, "Failed to fetch exchange rates:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Exchange rates fetched successfully",
+		Data:    rates,
+	})
+}
+
+// ImportExchangeRates stores rates sent as JSON, or as CSV in the format of
+// the file provider when the request has a text/csv body. Rates for a pair
+// and day that are already stored are replaced.
+func (h *ExchangeRateHandlers) ImportExchangeRates(ctx *gin.Context) {
+	var rates []models.ExchangeRate
+	var err error
+	if ctx.ContentType() == "text/csv" {
+		rates, err = exchange.ReadCSV(ctx.Request.Body)
+	} else {
+		rates, err = bindExchangeRates(ctx)
+	}
+	if err != nil {
+		logger.GetLogger().Error("Invalid exchange rate import:", err)
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	if err := h.exchangeRateRepo.Upsert(rates); err != nil {
+		respondExchangeRateError(ctx, "Failed to import exchange rates:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Exchange rates imported successfully",
+		Data:    gin.H{"imported": len(rates)},
+	})
+}
+
+func bindExchangeRates(ctx *gin.Context) ([]models.ExchangeRate, error) {
+	var importForm form.ImportExchangeRatesInput
+	if err := ctx.ShouldBindJSON(&importForm); err != nil {
+		return nil, err
+	}
+	if err := validate(importForm); err != nil {
+		return nil, err
+	}
+
+	rates := make([]models.ExchangeRate, len(importForm.Rates))
+	for i, input := range importForm.Rates {
+		rate, err := exchange.ParseRate(input.Date, input.From, input.To, input.Rate.String())
+		if err != nil {
+			return nil, err
+		}
+		rates[i] = rate
+	}
+	return rates, nil
+}
+
+func buildExchangeRateQuery(queryForm form.ExchangeRateQueryInput) (repository.ExchangeRateQuery, error) {
+	query := repository.ExchangeRateQuery{
+		From:  money.Currency(strings.ToUpper(queryForm.From)),
+		To:    money.Currency(strings.ToUpper(queryForm.To)),
+		Limit: queryForm.Limit,
+	}
+
+	var err error
+	if queryForm.Since != "" {
+		if query.Since, err = time.Parse(dateLayout, queryForm.Since); err != nil {
+			return query, err
+		}
+	}
+	if queryForm.Until != "" {
+		if query.Until, err = time.Parse(dateLayout, queryForm.Until); err != nil {
+			return query, err
+		}
+	}
+	return query, nil
+}
+
+func respondExchangeRateError(ctx *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repository.ErrSameCurrencyRate):
+		status = http.StatusBadRequest
+	default:
+		logger.GetLogger().Error(message, err)
+	}
+
+	ctx.JSON(status, &models.CustomResponse{
+		Status: status,
+		Error:  err.Error(),
+	})
+}
@@ -7,40 +7,97 @@ import (
 	"go-finance-tracker/internal/repository"
 	"go-finance-tracker/internal/rest/form"
 	"go-finance-tracker/pkg/logger"
+	"go-finance-tracker/pkg/money"
 	"net/http"
 	"strconv"
 )
 
 type FinanceHandlers struct {
-	financeRepo repository.FinanceRepo
+	financeRepo  repository.FinanceRepo
+	categoryRepo repository.CategoryRepo
+	accountRepo  repository.AccountRepo
 }
 
-func NewFinanceHandlers(financeRepo repository.FinanceRepo) *FinanceHandlers {
-	return &FinanceHandlers{financeRepo: financeRepo}
+func NewFinanceHandlers(financeRepo repository.FinanceRepo, categoryRepo repository.CategoryRepo, accountRepo repository.AccountRepo) *FinanceHandlers {
+	return &FinanceHandlers{
+		financeRepo:  financeRepo,
+		categoryRepo: categoryRepo,
+		accountRepo:  accountRepo,
+	}
 }
 
 func (h *FinanceHandlers) GetAllFinance(ctx *gin.Context) {
-	userIdStr, exists := ctx.Get("id")
-	if !exists {
-		logger.GetLogger().Error("User not authenticated")
-		ctx.JSON(http.StatusUnauthorized, &models.CustomResponse{
-			Status:  http.StatusUnauthorized,
-			Message: "User not authenticated",
+	id, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var queryForm form.FinanceQueryInput
+	if err := ctx.ShouldBindQuery(&queryForm); err != nil {
+		logger.GetLogger().Error("Invalid finance query:", err)
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
 		})
 		return
 	}
 
-	id, _ := strconv.Atoi(userIdStr.(string))
+	if err := validate(queryForm); err != nil {
+		logger.GetLogger().Error("Invalid finance query:", err)
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
 
-	finances, err := h.financeRepo.GetAll(id)
+	minAmount, err := parseOptionalAmount(queryForm.MinAmount)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  "minAmount: " + err.Error(),
+		})
+		return
+	}
+	maxAmount, err := parseOptionalAmount(queryForm.MaxAmount)
 	if err != nil {
-		if errors.Is(err, repository.ErrFinanceHistoryNotFound) {
-			ctx.JSON(http.StatusNotFound, &models.CustomResponse{
-				Status:  http.StatusNotFound,
-				Message: "User finance history not found:",
-				Error:   err.Error(),
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  "maxAmount: " + err.Error(),
+		})
+		return
+	}
+
+	query := repository.FinanceQuery{
+		UserID:            id,
+		From:              queryForm.From,
+		AccountID:         queryForm.AccountID,
+		CategoryID:        queryForm.CategoryID,
+		TransactionTypeID: queryForm.TransactionTypeID,
+		MinAmount:         minAmount,
+		MaxAmount:         maxAmount,
+		NoteContains:      queryForm.Note,
+		SortBy:            repository.FinanceSortField(queryForm.Sort),
+		SortDir:           repository.SortDirection(queryForm.Order),
+		Cursor:            queryForm.Cursor,
+		Limit:             queryForm.Limit,
+	}
+	if queryForm.To != nil {
+		// "to" is an inclusive day, the repository expects an exclusive bound.
+		to := queryForm.To.AddDate(0, 0, 1)
+		query.To = &to
+	}
+
+	page, err := h.financeRepo.Find(query)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidCursor) || errors.Is(err, repository.ErrInvalidSortField) {
+			ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+				Status: http.StatusBadRequest,
+				Error:  err.Error(),
 			})
+			return
 		}
+		logger.GetLogger().Error("Failed to fetch finance history:", err)
 		ctx.JSON(http.StatusInternalServerError, &models.CustomResponse{
 			Status: http.StatusInternalServerError,
 			Error:  err.Error(),
@@ -51,7 +108,7 @@ func (h *FinanceHandlers) GetAllFinance(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, &models.CustomResponse{
 		Status:  http.StatusOK,
 		Message: "User Finance History fetched successfully",
-		Data:    finances,
+		Data:    page,
 	})
 }
 
@@ -76,6 +133,13 @@ func (h *FinanceHandlers) AddFinanceRecord(ctx *gin.Context) {
 		})
 		return
 	}
+	if err := validate(financeForm); err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
 
 	var financeRecord models.FinanceRecord
 
@@ -85,14 +149,295 @@ func (h *FinanceHandlers) AddFinanceRecord(ctx *gin.Context) {
 	financeRecord.CategoryID = financeForm.CategoryID
 	financeRecord.Note = financeForm.Note
 
+	splits, ok := h.buildSplits(ctx, userID, financeForm.Splits)
+	if !ok {
+		return
+	}
+	financeRecord.Splits = splits
+	if financeRecord.CategoryID == 0 && len(splits) > 0 {
+		financeRecord.CategoryID = splits[0].CategoryID
+	}
+	if !h.checkCategoryVisible(ctx, userID, financeRecord.CategoryID) {
+		return
+	}
+	accountID, ok := resolveAccount(ctx, h.accountRepo, userID, financeForm.AccountID)
+	if !ok {
+		return
+	}
+	financeRecord.AccountID = accountID
+
 	if err := h.financeRepo.Create(&financeRecord); err != nil {
-		logger.GetLogger().Error("Failed to create finance record:", err)
+		respondFinanceError(ctx, "Failed to create finance record:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"data": "ok"})
+}
+
+func (h *FinanceHandlers) GetFinanceRecord(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	recordID, ok := getIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	record, err := h.financeRepo.GetByID(userID, recordID)
+	if err != nil {
+		respondFinanceError(ctx, "Failed to fetch finance record:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Finance record fetched successfully",
+		Data:    record,
+	})
+}
+
+func (h *FinanceHandlers) UpdateFinanceRecord(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	recordID, ok := getIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	var financeForm form.FinanceRecordInput
+	if err := ctx.ShouldBindJSON(&financeForm); err != nil {
+		logger.GetLogger().Error("Invalid finance record request:", err)
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+	if err := validate(financeForm); err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	record, err := h.financeRepo.GetByID(userID, recordID)
+	if err != nil {
+		respondFinanceError(ctx, "Failed to fetch finance record:", err)
+		return
+	}
+
+	record.Amount = financeForm.Amount
+	record.TransactionTypeID = financeForm.TransactionTypeID
+	record.CategoryID = financeForm.CategoryID
+	record.Note = financeForm.Note
+
+	splits, ok := h.buildSplits(ctx, userID, financeForm.Splits)
+	if !ok {
+		return
+	}
+	record.Splits = splits
+	if record.CategoryID == 0 && len(splits) > 0 {
+		record.CategoryID = splits[0].CategoryID
+	}
+	if !h.checkCategoryVisible(ctx, userID, record.CategoryID) {
+		return
+	}
+	// Without an account the record stays where it is.
+	if financeForm.AccountID != 0 && financeForm.AccountID != record.AccountID {
+		accountID, ok := resolveAccount(ctx, h.accountRepo, userID, financeForm.AccountID)
+		if !ok {
+			return
+		}
+		record.AccountID = accountID
+	}
+
+	if err := h.financeRepo.Update(record); err != nil {
+		respondFinanceError(ctx, "Failed to update finance record:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Finance record updated successfully",
+	})
+}
+
+func (h *FinanceHandlers) PatchFinanceRecord(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	recordID, ok := getIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	var patchForm form.FinanceRecordPatchInput
+	if err := ctx.ShouldBindJSON(&patchForm); err != nil {
+		logger.GetLogger().Error("Invalid finance record request:", err)
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+	if err := validate(patchForm); err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	record, err := h.financeRepo.GetByID(userID, recordID)
+	if err != nil {
+		respondFinanceError(ctx, "Failed to fetch finance record:", err)
+		return
+	}
+
+	if patchForm.AccountID != nil && *patchForm.AccountID != record.AccountID {
+		accountID, ok := resolveAccount(ctx, h.accountRepo, userID, *patchForm.AccountID)
+		if !ok {
+			return
+		}
+		record.AccountID = accountID
+	}
+	if patchForm.Amount != nil {
+		record.Amount = *patchForm.Amount
+	}
+	if patchForm.TransactionTypeID != nil {
+		record.TransactionTypeID = *patchForm.TransactionTypeID
+	}
+	if patchForm.CategoryID != nil {
+		if !h.checkCategoryVisible(ctx, userID, *patchForm.CategoryID) {
+			return
+		}
+		record.CategoryID = *patchForm.CategoryID
+	}
+	if patchForm.Note != nil {
+		record.Note = *patchForm.Note
+	}
+	if patchForm.Splits != nil {
+		splits, ok := h.buildSplits(ctx, userID, *patchForm.Splits)
+		if !ok {
+			return
+		}
+		record.Splits = splits
+		if patchForm.CategoryID == nil && len(splits) > 0 {
+			record.CategoryID = splits[0].CategoryID
+		}
+	}
+
+	if err := h.financeRepo.Update(record); err != nil {
+		respondFinanceError(ctx, "Failed to update finance record:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Finance record updated successfully",
+	})
+}
+
+func (h *FinanceHandlers) DeleteFinanceRecord(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	recordID, ok := getIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.financeRepo.Delete(userID, recordID); err != nil {
+		respondFinanceError(ctx, "Failed to delete finance record:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Finance record deleted successfully",
+	})
+}
+
+// checkCategoryVisible rejects records pointing at categories the user
+// cannot see, so that foreign categories are never attached to a record.
+func (h *FinanceHandlers) checkCategoryVisible(ctx *gin.Context, userID int, categoryID uint) bool {
+	if _, err := h.categoryRepo.GetVisible(userID, categoryID); err != nil {
+		if errors.Is(err, repository.ErrCategoryNotFound) {
+			ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+				Status: http.StatusBadRequest,
+				Error:  err.Error(),
+			})
+			return false
+		}
+		logger.GetLogger().Error("Failed to fetch category:", err)
 		ctx.JSON(http.StatusInternalServerError, &models.CustomResponse{
 			Status: http.StatusInternalServerError,
 			Error:  err.Error(),
 		})
+		return false
+	}
+	return true
+}
+
+// buildSplits turns the split lines of a request into models, rejecting
+// lines in categories the user cannot see.
+func (h *FinanceHandlers) buildSplits(ctx *gin.Context, userID int, inputs []form.SplitInput) ([]models.FinanceSplit, bool) {
+	if len(inputs) == 0 {
+		return nil, true
+	}
+
+	splits := make([]models.FinanceSplit, len(inputs))
+	for i, input := range inputs {
+		if !h.checkCategoryVisible(ctx, userID, input.CategoryID) {
+			return nil, false
+		}
+		splits[i] = models.FinanceSplit{
+			CategoryID: input.CategoryID,
+			Amount:     input.Amount,
+			Note:       input.Note,
+		}
+	}
+	return splits, true
+}
+
+func respondFinanceError(ctx *gin.Context, message string, err error) {
+	if errors.Is(err, repository.ErrFinanceRecordNotFound) {
+		ctx.JSON(http.StatusNotFound, &models.CustomResponse{
+			Status: http.StatusNotFound,
+			Error:  err.Error(),
+		})
+		return
+	}
+	if errors.Is(err, repository.ErrUnknownTransactionType) || errors.Is(err, repository.ErrTransferRecord) ||
+		errors.Is(err, repository.ErrInvalidSplits) || errors.Is(err, repository.ErrSplitCategory) ||
+		errors.Is(err, repository.ErrZeroAmount) {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"data": "ok"})
+	logger.GetLogger().Error(message, err)
+	ctx.JSON(http.StatusInternalServerError, &models.CustomResponse{
+		Status: http.StatusInternalServerError,
+		Error:  err.Error(),
+	})
+}
+
+func parseOptionalAmount(s string) (*money.Amount, error) {
+	if s == "" {
+		return nil, nil
+	}
+	amount, err := money.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	return &amount, nil
 }
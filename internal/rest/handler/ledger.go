@@ -0,0 +1,345 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go-finance-tracker/internal/ledger"
+	"go-finance-tracker/internal/models"
+	"go-finance-tracker/internal/repository"
+	"go-finance-tracker/internal/rest/form"
+	"go-finance-tracker/pkg/logger"
+	"go-finance-tracker/pkg/money"
+	"net/http"
+	"time"
+)
+
+type LedgerHandlers struct {
+	ledgerRepo repository.LedgerRepo
+}
+
+func NewLedgerHandlers(ledgerRepo repository.LedgerRepo) *LedgerHandlers {
+	return &LedgerHandlers{ledgerRepo: ledgerRepo}
+}
+
+// GetLedgerAccounts lists the user's chart of accounts.
+func (h *LedgerHandlers) GetLedgerAccounts(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	accounts, err := h.ledgerRepo.GetAccounts(userID)
+	if err != nil {
+		respondLedgerError(ctx, "Failed to fetch ledger accounts:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Ledger accounts fetched successfully",
+		Data:    accounts,
+	})
+}
+
+// CreateLedgerAccount opens an account that is not backed by an account or
+// a category, e.g. a loan.
+func (h *LedgerHandlers) CreateLedgerAccount(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var accountForm form.LedgerAccountInput
+	if err := ctx.ShouldBindJSON(&accountForm); err != nil {
+		logger.GetLogger().Error("Invalid ledger account request:", err)
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+	if err := validate(accountForm); err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	account := &models.LedgerAccount{
+		UserID: uint(userID),
+		Name:   accountForm.Name,
+		Type:   models.LedgerAccountType(accountForm.Type),
+	}
+	if err := h.ledgerRepo.CreateAccount(account); err != nil {
+		respondLedgerError(ctx, "Failed to create ledger account:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, &models.CustomResponse{
+		Status:  http.StatusCreated,
+		Message: "Ledger account created successfully",
+		Data:    account,
+	})
+}
+
+// GetJournalEntries lists journal entries with their postings, newest first.
+func (h *LedgerHandlers) GetJournalEntries(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var queryForm form.JournalQueryInput
+	if err := ctx.ShouldBindQuery(&queryForm); err != nil {
+		logger.GetLogger().Error("Invalid journal query:", err)
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+	if err := validate(queryForm); err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	page, err := h.ledgerRepo.FindEntries(repository.JournalQuery{
+		UserID:          userID,
+		LedgerAccountID: queryForm.LedgerAccountID,
+		Cursor:          queryForm.Cursor,
+		Limit:           queryForm.Limit,
+	})
+	if err != nil {
+		respondLedgerError(ctx, "Failed to fetch journal entries:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Journal entries fetched successfully",
+		Data:    page,
+	})
+}
+
+func (h *LedgerHandlers) GetJournalEntry(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	id, ok := getIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	entry, err := h.ledgerRepo.GetEntry(userID, id)
+	if err != nil {
+		respondLedgerError(ctx, "Failed to fetch journal entry:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Journal entry fetched successfully",
+		Data:    entry,
+	})
+}
+
+// CreateJournalEntry books a manual entry, which must balance in every
+// currency.
+func (h *LedgerHandlers) CreateJournalEntry(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var entryForm form.JournalEntryInput
+	if err := ctx.ShouldBindJSON(&entryForm); err != nil {
+		logger.GetLogger().Error("Invalid journal entry request:", err)
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+	if err := validate(entryForm); err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	entry := &models.JournalEntry{
+		UserID:      uint(userID),
+		Date:        time.Now(),
+		Description: entryForm.Description,
+		Postings:    make([]models.Posting, len(entryForm.Postings)),
+	}
+	if entryForm.Date != nil {
+		entry.Date = *entryForm.Date
+	}
+	for i, posting := range entryForm.Postings {
+		currency, err := money.ParseCurrency(posting.Currency)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+				Status: http.StatusBadRequest,
+				Error:  err.Error(),
+			})
+			return
+		}
+		entry.Postings[i] = models.Posting{
+			LedgerAccountID: posting.LedgerAccountID,
+			Amount:          posting.Amount,
+			Currency:        currency,
+		}
+	}
+
+	if err := h.ledgerRepo.CreateEntry(entry); err != nil {
+		respondLedgerError(ctx, "Failed to create journal entry:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, &models.CustomResponse{
+		Status:  http.StatusCreated,
+		Message: "Journal entry created successfully",
+		Data:    entry,
+	})
+}
+
+// DeleteJournalEntry removes a manual entry. Entries of finance records go
+// away with the records.
+func (h *LedgerHandlers) DeleteJournalEntry(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	id, ok := getIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.ledgerRepo.DeleteEntry(userID, id); err != nil {
+		respondLedgerError(ctx, "Failed to delete journal entry:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Journal entry deleted successfully",
+	})
+}
+
+func (h *LedgerHandlers) GetTrialBalance(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	at, ok := bindLedgerReportDate(ctx)
+	if !ok {
+		return
+	}
+
+	trialBalance, err := h.ledgerRepo.TrialBalance(userID, at)
+	if err != nil {
+		respondLedgerError(ctx, "Failed to build trial balance:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Trial balance built successfully",
+		Data:    trialBalance,
+	})
+}
+
+func (h *LedgerHandlers) GetBalanceSheet(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	at, ok := bindLedgerReportDate(ctx)
+	if !ok {
+		return
+	}
+
+	balanceSheet, err := h.ledgerRepo.BalanceSheet(userID, at)
+	if err != nil {
+		respondLedgerError(ctx, "Failed to build balance sheet:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Balance sheet built successfully",
+		Data:    balanceSheet,
+	})
+}
+
+// bindLedgerReportDate returns the exclusive end of the day the report is
+// as of: the end of the requested day, or now.
+func bindLedgerReportDate(ctx *gin.Context) (time.Time, bool) {
+	var reportForm form.LedgerReportInput
+	if err := ctx.ShouldBindQuery(&reportForm); err != nil {
+		logger.GetLogger().Error("Invalid ledger report query:", err)
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return time.Time{}, false
+	}
+	if reportForm.At == "" {
+		return time.Now(), true
+	}
+
+	location := time.UTC
+	if reportForm.Timezone != "" {
+		var err error
+		if location, err = loadLocation(reportForm.Timezone); err != nil {
+			ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+				Status: http.StatusBadRequest,
+				Error:  err.Error(),
+			})
+			return time.Time{}, false
+		}
+	}
+	day, err := time.ParseInLocation(dateLayout, reportForm.At, location)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return time.Time{}, false
+	}
+	return day.AddDate(0, 0, 1), true
+}
+
+func respondLedgerError(ctx *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repository.ErrLedgerAccountNotFound),
+		errors.Is(err, repository.ErrJournalEntryNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, repository.ErrLedgerAccountExists):
+		status = http.StatusConflict
+	case errors.Is(err, repository.ErrLedgerAccountType),
+		errors.Is(err, repository.ErrLedgerAccountLinked),
+		errors.Is(err, repository.ErrJournalEntryManaged),
+		errors.Is(err, repository.ErrInvalidCursor),
+		errors.Is(err, ledger.ErrTooFewPostings),
+		errors.Is(err, ledger.ErrZeroPosting),
+		errors.Is(err, ledger.ErrNoCurrency),
+		errors.Is(err, ledger.ErrUnbalanced):
+		status = http.StatusBadRequest
+	default:
+		logger.GetLogger().Error(message, err)
+	}
+
+	ctx.JSON(status, &models.CustomResponse{
+		Status: status,
+		Error:  err.Error(),
+	})
+}
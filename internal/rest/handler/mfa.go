@@ -0,0 +1,447 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go-finance-tracker/internal/models"
+	"go-finance-tracker/internal/repository"
+	"go-finance-tracker/internal/rest/form"
+	"go-finance-tracker/pkg/logger"
+	"go-finance-tracker/pkg/totp"
+	"go-finance-tracker/pkg/utils"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	recoveryCodeCount = 10
+	// totpSkew is how many steps a code may be off, to allow for clock drift
+	// between the server and the authenticator.
+	totpSkew              = 1
+	recoveryCodeSeparator = "-"
+)
+
+var (
+	errMFANotConfigured = errors.New("two-factor authentication is not configured on this server")
+	errMFANotEnrolled   = errors.New("two-factor enrollment has not been started")
+	errInvalidMFACode   = errors.New("authentication code is invalid")
+	errInvalidMFAToken  = errors.New("two-factor login token is invalid or expired")
+)
+
+type mfaEnrollment struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+type mfaChallenge struct {
+	MFARequired bool      `json:"mfaRequired"`
+	MFAToken    string    `json:"mfaToken"`
+	ExpiresAt   time.Time `json:"expiresAt"`
+}
+
+type recoveryCodes struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// EnrollMFA starts two-factor enrollment by generating a TOTP secret. The
+// secret and its otpauth URI are returned for the authenticator app; 2FA is
+// only enabled once a code is confirmed with ConfirmMFA.
+func (h *AuthHandlers) EnrollMFA(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	if h.Config.MFAEncryptionKey == "" {
+		respondMFAError(ctx, "", errMFANotConfigured)
+		return
+	}
+
+	user, err := h.UserRepo.GetUserByID(uint(userID))
+	if err != nil {
+		respondMFAError(ctx, "User does not exist:", err)
+		return
+	}
+	if user.IsMFAEnabled() {
+		respondMFAError(ctx, "", repository.ErrMFAAlreadyEnabled)
+		return
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		respondMFAError(ctx, "Failed to generate TOTP secret:", err)
+		return
+	}
+	encrypted, err := utils.Encrypt(h.Config.MFAEncryptionKey, secret)
+	if err != nil {
+		respondMFAError(ctx, "Failed to encrypt TOTP secret:", err)
+		return
+	}
+	if err := h.MFARepo.SetPendingSecret(user.ID, encrypted); err != nil {
+		respondMFAError(ctx, "Failed to store TOTP secret:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Scan the code with your authenticator app and confirm it",
+		Data: mfaEnrollment{
+			Secret: secret,
+			URI:    totp.URI(h.Config.MFAIssuer, user.Email, secret),
+		},
+	})
+}
+
+// ConfirmMFA enables two-factor authentication once the user proves their
+// authenticator works, and returns the recovery codes. They are shown only
+// this once.
+func (h *AuthHandlers) ConfirmMFA(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var codeForm form.MFACodeInput
+	if !bindMFAForm(ctx, &codeForm) {
+		return
+	}
+
+	user, err := h.UserRepo.GetUserByID(uint(userID))
+	if err != nil {
+		respondMFAError(ctx, "User does not exist:", err)
+		return
+	}
+	if user.IsMFAEnabled() {
+		respondMFAError(ctx, "", repository.ErrMFAAlreadyEnabled)
+		return
+	}
+	if user.TOTPSecret == "" {
+		respondMFAError(ctx, "", errMFANotEnrolled)
+		return
+	}
+
+	secret, err := h.totpSecret(user)
+	if err != nil {
+		respondMFAError(ctx, "Failed to decrypt TOTP secret:", err)
+		return
+	}
+	step, ok := totp.Validate(secret, codeForm.Code, time.Now(), totpSkew)
+	if !ok {
+		respondMFAError(ctx, "", errInvalidMFACode)
+		return
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		respondMFAError(ctx, "Failed to generate recovery codes:", err)
+		return
+	}
+	if err := h.MFARepo.Enable(user.ID, step, hashes); err != nil {
+		respondMFAError(ctx, "Failed to enable two-factor authentication:", err)
+		return
+	}
+
+	logger.GetLogger().Info("Two-factor authentication enabled for user ID:", user.ID)
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Two-factor authentication enabled, store the recovery codes safely",
+		Data:    recoveryCodes{RecoveryCodes: codes},
+	})
+}
+
+// DisableMFA turns off two-factor authentication. Both the password and a
+// current code or recovery code are required.
+func (h *AuthHandlers) DisableMFA(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var disableForm form.DisableMFAInput
+	if !bindMFAForm(ctx, &disableForm) {
+		return
+	}
+
+	user, err := h.UserRepo.GetUserByID(uint(userID))
+	if err != nil {
+		respondMFAError(ctx, "User does not exist:", err)
+		return
+	}
+	if !user.IsMFAEnabled() {
+		respondMFAError(ctx, "", repository.ErrMFANotEnabled)
+		return
+	}
+	if !utils.CheckPasswordHash(disableForm.Password, user.Password) {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Current password is incorrect",
+		})
+		return
+	}
+	if _, err := h.verifyMFACode(user, disableForm.Code); err != nil {
+		respondMFAError(ctx, "Failed to verify authentication code:", err)
+		return
+	}
+
+	if err := h.MFARepo.Disable(user.ID, nil); err != nil {
+		respondMFAError(ctx, "Failed to disable two-factor authentication:", err)
+		return
+	}
+
+	logger.GetLogger().Info("Two-factor authentication disabled for user ID:", user.ID)
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Two-factor authentication disabled",
+	})
+}
+
+// RegenerateRecoveryCodes replaces the recovery codes after checking a
+// current code. The old codes stop working.
+func (h *AuthHandlers) RegenerateRecoveryCodes(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var codeForm form.MFACodeInput
+	if !bindMFAForm(ctx, &codeForm) {
+		return
+	}
+
+	user, err := h.UserRepo.GetUserByID(uint(userID))
+	if err != nil {
+		respondMFAError(ctx, "User does not exist:", err)
+		return
+	}
+	if !user.IsMFAEnabled() {
+		respondMFAError(ctx, "", repository.ErrMFANotEnabled)
+		return
+	}
+	if _, err := h.verifyMFACode(user, codeForm.Code); err != nil {
+		respondMFAError(ctx, "Failed to verify authentication code:", err)
+		return
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		respondMFAError(ctx, "Failed to generate recovery codes:", err)
+		return
+	}
+	if err := h.MFARepo.ReplaceRecoveryCodes(user.ID, hashes); err != nil {
+		respondMFAError(ctx, "Failed to store recovery codes:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Recovery codes regenerated, store them safely",
+		Data:    recoveryCodes{RecoveryCodes: codes},
+	})
+}
+
+// LoginMFA is the second step of a login with two-factor authentication. It
+// exchanges the token returned by Login and a TOTP or recovery code for a
+// session. Wrong codes count as failed logins.
+func (h *AuthHandlers) LoginMFA(ctx *gin.Context) {
+	var mfaForm form.MFALoginInput
+	if !bindMFAForm(ctx, &mfaForm) {
+		return
+	}
+
+	claims, err := utils.ParseToken(mfaForm.MFAToken)
+	if err != nil || claims.Purpose != models.MFAPendingPurpose {
+		respondMFAError(ctx, "", errInvalidMFAToken)
+		return
+	}
+	id, err := strconv.Atoi(claims.Id)
+	if err != nil {
+		respondMFAError(ctx, "", errInvalidMFAToken)
+		return
+	}
+
+	ip := ctx.ClientIP()
+	if !h.checkLoginIP(ctx, ip) {
+		return
+	}
+
+	user, err := h.UserRepo.GetUserByID(uint(id))
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			err = errInvalidMFAToken
+		}
+		respondMFAError(ctx, "Failed to fetch user:", err)
+		return
+	}
+	// 2FA may have been reset, or the password changed, since the token was
+	// issued.
+	if !user.IsMFAEnabled() ||
+		claims.TokenVersion != user.TokenVersion {
+		respondMFAError(ctx, "", errInvalidMFAToken)
+		return
+	}
+	if user.IsLocked() {
+		ctx.JSON(http.StatusForbidden, &models.CustomResponse{
+			Status:  http.StatusForbidden,
+			Message: "Account is locked",
+		})
+		return
+	}
+	if !h.checkLoginThrottle(ctx, user) {
+		return
+	}
+
+	attempt := &models.LoginAttempt{Username: user.Username, UserID: &user.ID, IP: ip}
+	usedRecoveryCode, err := h.verifyMFACode(user, mfaForm.Code)
+	if err != nil {
+		if errors.Is(err, errInvalidMFACode) {
+			logger.GetLogger().Error("Bad authentication code for username:", user.Username)
+			h.recordLoginFailure(attempt, user)
+		}
+		respondMFAError(ctx, "Failed to verify authentication code:", err)
+		return
+	}
+	if usedRecoveryCode {
+		logger.GetLogger().Info("Recovery code used by user ID:", user.ID)
+	}
+
+	h.completeLogin(ctx, attempt, user, mfaForm.Device, mfaForm.ReturnTokens)
+}
+
+// requireMFA answers a login whose password was accepted with a short-lived
+// token for LoginMFA instead of a session.
+func (h *AuthHandlers) requireMFA(ctx *gin.Context, user *models.User) {
+	token, err := utils.CreateToken(models.Claims{
+		Id:           strconv.Itoa(int(user.ID)),
+		Username:     user.Username,
+		TokenVersion: user.TokenVersion,
+		Purpose:      models.MFAPendingPurpose,
+	}, h.Config.MFATokenTTL)
+	if err != nil {
+		respondMFAError(ctx, "Failed to generate jwt token:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Two-factor authentication required",
+		Data: mfaChallenge{
+			MFARequired: true,
+			MFAToken:    token,
+			ExpiresAt:   time.Now().Add(h.Config.MFATokenTTL),
+		},
+	})
+}
+
+// verifyMFACode accepts a TOTP code or an unused recovery code and uses it
+// up. It reports whether a recovery code was used, and returns
+// errInvalidMFACode when code is neither.
+func (h *AuthHandlers) verifyMFACode(user *models.User, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		secret, err := h.totpSecret(user)
+		if err != nil {
+			return false, err
+		}
+		step, ok := totp.Validate(secret, code, time.Now(), totpSkew)
+		if !ok {
+			return false, errInvalidMFACode
+		}
+		fresh, err := h.MFARepo.UseStep(user.ID, step)
+		if err != nil {
+			return false, err
+		}
+		if !fresh {
+			return false, errInvalidMFACode
+		}
+		return false, nil
+	}
+
+	used, err := h.MFARepo.UseRecoveryCode(user.ID, hashRecoveryCode(code))
+	if err != nil {
+		return false, err
+	}
+	if !used {
+		return false, errInvalidMFACode
+	}
+	return true, nil
+}
+
+func (h *AuthHandlers) totpSecret(user *models.User) (string, error) {
+	if h.Config.MFAEncryptionKey == "" {
+		return "", errMFANotConfigured
+	}
+	return utils.Decrypt(h.Config.MFAEncryptionKey, user.TOTPSecret)
+}
+
+// generateRecoveryCodes returns new recovery codes, formatted as two groups
+// of five characters, along with the hashes to store.
+func generateRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		buf := make([]byte, 10)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, nil, err
+		}
+		code := strings.ToLower(base32.StdEncoding.EncodeToString(buf)[:10])
+		codes[i] = code[:5] + recoveryCodeSeparator + code[5:]
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+	return codes, hashes, nil
+}
+
+// hashRecoveryCode hashes a recovery code, ignoring case and separators so
+// that users can type it either way.
+func hashRecoveryCode(code string) string {
+	code = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), recoveryCodeSeparator, ""))
+	return utils.HashOpaqueToken(code)
+}
+
+// bindMFAForm binds and validates the JSON body into input, answering the
+// request itself when that fails.
+func bindMFAForm(ctx *gin.Context, input any) bool {
+	if err := ctx.ShouldBindJSON(input); err != nil {
+		logger.GetLogger().Error("Invalid two-factor request:", err)
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return false
+	}
+	if err := validate(input); err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return false
+	}
+	return true
+}
+
+func respondMFAError(ctx *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repository.ErrUserNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, repository.ErrMFAAlreadyEnabled):
+		status = http.StatusConflict
+	case errors.Is(err, errInvalidMFAToken):
+		status = http.StatusUnauthorized
+	case errors.Is(err, repository.ErrMFANotEnabled),
+		errors.Is(err, errMFANotEnrolled),
+		errors.Is(err, errInvalidMFACode):
+		status = http.StatusBadRequest
+	case errors.Is(err, errMFANotConfigured):
+		status = http.StatusServiceUnavailable
+	default:
+		logger.GetLogger().Error(message, err)
+	}
+
+	ctx.JSON(status, &models.CustomResponse{
+		Status: status,
+		Error:  err.Error(),
+	})
+}
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go-finance-tracker/internal/models"
+	"go-finance-tracker/internal/repository"
+	"go-finance-tracker/internal/rest/form"
+	"go-finance-tracker/pkg/email"
+	"go-finance-tracker/pkg/logger"
+	"go-finance-tracker/pkg/utils"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	passwordResetTTL      = time.Hour
+	passwordResetTemplate = "templates/reset_password.html"
+)
+
+// ForgotPassword emails a single-use reset link. The response is the same
+// whether or not the address is registered, so it cannot be used to probe
+// for accounts.
+func (h *AuthHandlers) ForgotPassword(ctx *gin.Context) {
+	var forgotForm form.ForgotPasswordInput
+	if err := ctx.ShouldBindJSON(&forgotForm); err != nil {
+		logger.GetLogger().Error("Invalid forgot password request:", err)
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+	if err := validate(forgotForm); err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	response := &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "If the address is registered, a password reset link has been sent",
+	}
+
+	user, err := h.UserRepo.GetUserByEmail(forgotForm.Email)
+	if err != nil {
+		if !errors.Is(err, repository.ErrUserNotFound) {
+			logger.GetLogger().Error("Failed to look up user by email:", err)
+		}
+		ctx.JSON(http.StatusOK, response)
+		return
+	}
+
+	if err := h.sendPasswordReset(user); err != nil {
+		logger.GetLogger().Error("Failed to issue password reset token:", err)
+		ctx.JSON(http.StatusInternalServerError, &models.CustomResponse{
+			Status: http.StatusInternalServerError,
+			Error:  "Failed to issue password reset token",
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, response)
+}
+
+// ResetPassword sets a new password using a token from ForgotPassword. Every
+// token issued to the user before the reset stops being accepted.
+func (h *AuthHandlers) ResetPassword(ctx *gin.Context) {
+	var resetForm form.ResetPasswordInput
+	if err := ctx.ShouldBindJSON(&resetForm); err != nil {
+		logger.GetLogger().Error("Invalid reset password request:", err)
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+	if err := validate(resetForm); err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	// The policy refuses passwords containing the username or email, so it
+	// needs the user the token was issued to. ResetPassword checks the token
+	// again when it consumes it.
+	tokenHash := utils.HashOpaqueToken(resetForm.Token)
+	user, err := h.UserTokenRepo.GetTokenUser(models.PasswordResetToken, tokenHash)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidUserToken) {
+			ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+				Status: http.StatusBadRequest,
+				Error:  err.Error(),
+			})
+			return
+		}
+		logger.GetLogger().Error("Failed to fetch reset token:", err)
+		ctx.JSON(http.StatusInternalServerError, &models.CustomResponse{
+			Status: http.StatusInternalServerError,
+			Error:  err.Error(),
+		})
+		return
+	}
+	if err := utils.CheckPasswordPolicy(resetForm.Password, user.Username, user.Email); err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	hashedPassword, err := utils.HashPassword(resetForm.Password)
+	if err != nil {
+		logger.GetLogger().Error("Unable to hash the password")
+		ctx.JSON(http.StatusInternalServerError, &models.CustomResponse{
+			Status: http.StatusInternalServerError,
+			Error:  "Unable to hash the password",
+		})
+		return
+	}
+
+	userID, err := h.UserTokenRepo.ResetPassword(tokenHash, hashedPassword)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidUserToken) {
+			ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+				Status: http.StatusBadRequest,
+				Error:  err.Error(),
+			})
+			return
+		}
+		logger.GetLogger().Error("Failed to reset password:", err)
+		ctx.JSON(http.StatusInternalServerError, &models.CustomResponse{
+			Status: http.StatusInternalServerError,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	logger.GetLogger().Info("Password reset for user ID:", userID)
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Password has been reset, please log in again",
+	})
+}
+
+// sendPasswordReset stores a new reset token for user and emails the link.
+// The email is sent in the background so response times do not reveal
+// whether an account exists.
+func (h *AuthHandlers) sendPasswordReset(user *models.User) error {
+	token, tokenHash, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	if err := h.UserTokenRepo.Issue(&models.UserToken{
+		UserID:    user.ID,
+		Purpose:   models.PasswordResetToken,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(passwordResetTTL),
+	}); err != nil {
+		return err
+	}
+
+	input := email.SendEmailInput{
+		To:      user.Email,
+		Subject: "Reset your password",
+	}
+	if err := input.GenerateBodyFromHTML(passwordResetTemplate, map[string]string{
+		"Link":      h.Config.AppURL + "/reset-password?token=" + url.QueryEscape(token),
+		"ExpiresIn": "1 hour",
+	}); err != nil {
+		return err
+	}
+
+	go func() {
+		if err := h.EmailSender.Send(input); err != nil {
+			logger.GetLogger().Error("Failed to send password reset email:", err)
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,254 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go-finance-tracker/internal/models"
+	"go-finance-tracker/internal/repository"
+	"go-finance-tracker/internal/rest/form"
+	"go-finance-tracker/pkg/logger"
+	"go-finance-tracker/pkg/money"
+	"go-finance-tracker/pkg/utils"
+	"net/http"
+	"strings"
+)
+
+var errEmailTaken = errors.New("email is already registered to another account")
+
+// UpdateProfile changes the authenticated user's name, surname, email or the
+// base currency reports are converted to. A new email is marked unverified
+// and a verification link is sent to it.
+func (h *AuthHandlers) UpdateProfile(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var profileForm form.ProfileInput
+	if err := ctx.ShouldBindJSON(&profileForm); err != nil {
+		logger.GetLogger().Error("Invalid profile request:", err)
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+	if err := validate(profileForm); err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	user, err := h.UserRepo.GetUserByID(uint(userID))
+	if err != nil {
+		logger.GetLogger().Error("User does not exist:", err)
+		ctx.JSON(http.StatusNotFound, &models.CustomResponse{
+			Status: http.StatusNotFound,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	changes := applyProfile(user, profileForm)
+	if _, ok := changes["email"]; ok {
+		if err := checkEmailAvailable(h.UserRepo, user.Email, user.ID); err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, errEmailTaken) {
+				status = http.StatusConflict
+			} else {
+				logger.GetLogger().Error("Failed to check email:", err)
+			}
+			ctx.JSON(status, &models.CustomResponse{
+				Status: status,
+				Error:  err.Error(),
+			})
+			return
+		}
+	}
+
+	if len(changes) > 0 {
+		if err := h.UserRepo.UpdateUser(user, nil); err != nil {
+			logger.GetLogger().Error("Failed to update profile:", err)
+			ctx.JSON(http.StatusInternalServerError, &models.CustomResponse{
+				Status: http.StatusInternalServerError,
+				Error:  err.Error(),
+			})
+			return
+		}
+
+		if !user.IsEmailVerified() {
+			if err := h.sendEmailVerification(user); err != nil {
+				logger.GetLogger().Error("Failed to issue verification token:", err)
+			}
+		}
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Profile updated successfully",
+		Data:    user,
+	})
+}
+
+// ChangePassword sets a new password after checking the current one. All
+// other sessions are revoked; the current one gets a fresh access token,
+// which is also returned for clients using the Authorization header.
+func (h *AuthHandlers) ChangePassword(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var passwordForm form.ChangePasswordInput
+	if err := ctx.ShouldBindJSON(&passwordForm); err != nil {
+		logger.GetLogger().Error("Invalid change password request:", err)
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+	if err := validate(passwordForm); err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	user, err := h.UserRepo.GetUserByID(uint(userID))
+	if err != nil {
+		logger.GetLogger().Error("User does not exist:", err)
+		ctx.JSON(http.StatusNotFound, &models.CustomResponse{
+			Status: http.StatusNotFound,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	if !utils.CheckPasswordHash(passwordForm.CurrentPassword, user.Password) {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Current password is incorrect",
+		})
+		return
+	}
+	if passwordForm.NewPassword == passwordForm.CurrentPassword {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status:  http.StatusBadRequest,
+			Message: "New password must differ from the current one",
+		})
+		return
+	}
+	if err := utils.CheckPasswordPolicy(passwordForm.NewPassword, user.Username, user.Email); err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	hashedPassword, err := utils.HashPassword(passwordForm.NewPassword)
+	if err != nil {
+		logger.GetLogger().Error("Unable to hash the password")
+		ctx.JSON(http.StatusInternalServerError, &models.CustomResponse{
+			Status: http.StatusInternalServerError,
+			Error:  "Unable to hash the password",
+		})
+		return
+	}
+
+	sessionID := ctx.GetUint("sessionID")
+	if err := h.UserRepo.ChangePassword(user.ID, hashedPassword, sessionID); err != nil {
+		logger.GetLogger().Error("Failed to change password:", err)
+		ctx.JSON(http.StatusInternalServerError, &models.CustomResponse{
+			Status: http.StatusInternalServerError,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	// The current access token carries the old token version and is no
+	// longer accepted, so replace it with one of the new version.
+	user, err = h.UserRepo.GetUserByID(user.ID)
+	if err != nil {
+		logger.GetLogger().Error("Failed to fetch user:", err)
+		ctx.JSON(http.StatusInternalServerError, &models.CustomResponse{
+			Status: http.StatusInternalServerError,
+			Error:  err.Error(),
+		})
+		return
+	}
+	session, err := h.SessionRepo.GetByID(sessionID)
+	if err != nil {
+		logger.GetLogger().Error("Failed to fetch session:", err)
+		ctx.JSON(http.StatusInternalServerError, &models.CustomResponse{
+			Status: http.StatusInternalServerError,
+			Error:  err.Error(),
+		})
+		return
+	}
+	tokens, err := h.issueTokens(user, session, "")
+	if err != nil {
+		logger.GetLogger().Error("Failed to generate jwt token:", err)
+		ctx.JSON(http.StatusInternalServerError, &models.CustomResponse{
+			Status: http.StatusInternalServerError,
+			Error:  err.Error(),
+		})
+		return
+	}
+	http.SetCookie(ctx.Writer, h.cookie(accessTokenCookie, tokens.AccessToken, "/", tokens.ExpiresAt))
+
+	logger.GetLogger().Info("Password changed for user ID:", user.ID)
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Password changed successfully, other sessions were signed out",
+		Data:    tokens,
+	})
+}
+
+// applyProfile copies the set fields of input onto user and returns the
+// changed fields as old and new values. Changing the email marks it as
+// unverified.
+func applyProfile(user *models.User, input form.ProfileInput) models.AuditDetails {
+	changes := models.AuditDetails{}
+	if input.Name != nil && *input.Name != user.Name {
+		changes["name"] = []string{user.Name, *input.Name}
+		user.Name = *input.Name
+	}
+	if input.Surname != nil && *input.Surname != user.Surname {
+		changes["surname"] = []string{user.Surname, *input.Surname}
+		user.Surname = *input.Surname
+	}
+	if input.Email != nil && !strings.EqualFold(*input.Email, user.Email) {
+		changes["email"] = []string{user.Email, *input.Email}
+		user.Email = *input.Email
+		user.EmailVerifiedAt = nil
+	}
+	if input.BaseCurrency != nil {
+		currency := money.Currency(strings.ToUpper(*input.BaseCurrency))
+		if currency != user.BaseCurrency {
+			changes["baseCurrency"] = []string{string(user.BaseCurrency), string(currency)}
+			user.BaseCurrency = currency
+		}
+	}
+	return changes
+}
+
+// checkEmailAvailable returns errEmailTaken when email belongs to a user
+// other than userID.
+func checkEmailAvailable(userRepo repository.UserRepo, email string, userID uint) error {
+	existing, err := userRepo.GetUserByEmail(email)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+	if existing.ID != userID {
+		return errEmailTaken
+	}
+	return nil
+}
@@ -0,0 +1,285 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go-finance-tracker/internal/models"
+	"go-finance-tracker/internal/repository"
+	"go-finance-tracker/internal/rest/form"
+	"go-finance-tracker/pkg/logger"
+	"net/http"
+)
+
+const defaultPreviewCount = 5
+
+var errEndBeforeStart = errors.New("endDate must not be before startDate")
+
+type RecurringHandlers struct {
+	recurringRepo repository.RecurringRepo
+	categoryRepo  repository.CategoryRepo
+	accountRepo   repository.AccountRepo
+}
+
+func NewRecurringHandlers(recurringRepo repository.RecurringRepo, categoryRepo repository.CategoryRepo, accountRepo repository.AccountRepo) *RecurringHandlers {
+	return &RecurringHandlers{
+		recurringRepo: recurringRepo,
+		categoryRepo:  categoryRepo,
+		accountRepo:   accountRepo,
+	}
+}
+
+func (h *RecurringHandlers) GetAllRecurringRules(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	rules, err := h.recurringRepo.GetAll(userID)
+	if err != nil {
+		respondRecurringError(ctx, "Failed to fetch recurring rules:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Recurring rules fetched successfully",
+		Data:    rules,
+	})
+}
+
+func (h *RecurringHandlers) GetRecurringRule(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	ruleID, ok := getIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	rule, err := h.recurringRepo.GetByID(userID, ruleID)
+	if err != nil {
+		respondRecurringError(ctx, "Failed to fetch recurring rule:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Recurring rule fetched successfully",
+		Data:    rule,
+	})
+}
+
+func (h *RecurringHandlers) CreateRecurringRule(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	rule, ok := h.bindRecurringRule(ctx, userID)
+	if !ok {
+		return
+	}
+	if rule.AccountID == 0 {
+		if rule.AccountID, ok = resolveAccount(ctx, h.accountRepo, userID, 0); !ok {
+			return
+		}
+	}
+
+	if err := h.recurringRepo.Create(rule); err != nil {
+		respondRecurringError(ctx, "Failed to create recurring rule:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, &models.CustomResponse{
+		Status:  http.StatusCreated,
+		Message: "Recurring rule created successfully",
+		Data:    rule,
+	})
+}
+
+// UpdateRecurringRule changes the account, amount, type, category, note and
+// end of a rule. Schedule fields in the body are ignored, and without an
+// account the rule keeps its own.
+func (h *RecurringHandlers) UpdateRecurringRule(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	ruleID, ok := getIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	rule, ok := h.bindRecurringRule(ctx, userID)
+	if !ok {
+		return
+	}
+	rule.ID = ruleID
+
+	if err := h.recurringRepo.Update(rule); err != nil {
+		respondRecurringError(ctx, "Failed to update recurring rule:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Recurring rule updated successfully",
+		Data:    rule,
+	})
+}
+
+func (h *RecurringHandlers) DeleteRecurringRule(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	ruleID, ok := getIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.recurringRepo.Delete(userID, ruleID); err != nil {
+		respondRecurringError(ctx, "Failed to delete recurring rule:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Recurring rule deleted successfully",
+	})
+}
+
+// PreviewRecurringRule lists the next occurrences that have not been
+// materialized yet.
+func (h *RecurringHandlers) PreviewRecurringRule(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	ruleID, ok := getIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	var previewForm form.RecurringPreviewInput
+	if err := ctx.ShouldBindQuery(&previewForm); err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+	if err := validate(previewForm); err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+	count := previewForm.Count
+	if count == 0 {
+		count = defaultPreviewCount
+	}
+
+	rule, err := h.recurringRepo.GetByID(userID, ruleID)
+	if err != nil {
+		respondRecurringError(ctx, "Failed to fetch recurring rule:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Recurring rule preview built successfully",
+		Data:    rule.Occurrences(rule.NextOccurrence, count),
+	})
+}
+
+func (h *RecurringHandlers) bindRecurringRule(ctx *gin.Context, userID int) (*models.RecurringRule, bool) {
+	var ruleForm form.RecurringRuleInput
+	if err := ctx.ShouldBindJSON(&ruleForm); err != nil {
+		logger.GetLogger().Error("Invalid recurring rule request:", err)
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return nil, false
+	}
+	if err := validate(ruleForm); err != nil {
+		logger.GetLogger().Error("Invalid recurring rule request:", err)
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return nil, false
+	}
+
+	timezone := ruleForm.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	if _, err := loadLocation(timezone); err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return nil, false
+	}
+	if ruleForm.EndDate != nil && ruleForm.EndDate.Before(ruleForm.StartDate) {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  errEndBeforeStart.Error(),
+		})
+		return nil, false
+	}
+
+	if _, err := h.categoryRepo.GetVisible(userID, ruleForm.CategoryID); err != nil {
+		respondRecurringError(ctx, "Failed to fetch category:", err)
+		return nil, false
+	}
+	if ruleForm.AccountID != 0 {
+		if _, ok := resolveAccount(ctx, h.accountRepo, userID, ruleForm.AccountID); !ok {
+			return nil, false
+		}
+	}
+
+	interval := ruleForm.Interval
+	if interval == 0 {
+		interval = 1
+	}
+
+	return &models.RecurringRule{
+		UserID:            uint(userID),
+		AccountID:         ruleForm.AccountID,
+		Amount:            ruleForm.Amount,
+		TransactionTypeID: ruleForm.TransactionTypeID,
+		CategoryID:        ruleForm.CategoryID,
+		Note:              ruleForm.Note,
+		Frequency:         models.RecurrenceFrequency(ruleForm.Frequency),
+		Interval:          interval,
+		DayOfMonth:        ruleForm.DayOfMonth,
+		Timezone:          timezone,
+		StartDate:         ruleForm.StartDate,
+		EndDate:           ruleForm.EndDate,
+		Count:             ruleForm.Count,
+	}, true
+}
+
+func respondRecurringError(ctx *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repository.ErrRecurringRuleNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, repository.ErrCategoryNotFound),
+		errors.Is(err, repository.ErrUnknownTransactionType),
+		errors.Is(err, repository.ErrTransferRecord):
+		status = http.StatusBadRequest
+	default:
+		logger.GetLogger().Error(message, err)
+	}
+
+	ctx.JSON(status, &models.CustomResponse{
+		Status: status,
+		Error:  err.Error(),
+	})
+}
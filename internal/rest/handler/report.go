@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go-finance-tracker/internal/models"
+	"go-finance-tracker/internal/repository"
+	"go-finance-tracker/internal/rest/form"
+	"go-finance-tracker/pkg/logger"
+	"go-finance-tracker/pkg/money"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const dateLayout = "2006-01-02"
+
+var (
+	errInvalidDateRange = errors.New("from must be before to")
+	errInvalidTimezone  = errors.New("timezone must be an IANA name such as Asia/Almaty")
+)
+
+type ReportHandlers struct {
+	reportRepo repository.ReportRepo
+}
+
+func NewReportHandlers(reportRepo repository.ReportRepo) *ReportHandlers {
+	return &ReportHandlers{reportRepo: reportRepo}
+}
+
+// GetSummary reports income, expense and net totals for a period, broken
+// down by sub-period and category. Without dates it covers the current month.
+// Records in other currencies are converted to the report currency.
+func (h *ReportHandlers) GetSummary(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var queryForm form.SummaryQueryInput
+	if err := ctx.ShouldBindQuery(&queryForm); err != nil {
+		logger.GetLogger().Error("Invalid summary query:", err)
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+	if err := validate(queryForm); err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	query, err := buildSummaryQuery(userID, queryForm, time.Now())
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	summary, err := h.reportRepo.Summary(query)
+	if err != nil {
+		logger.GetLogger().Error("Failed to build summary report:", err)
+		ctx.JSON(http.StatusInternalServerError, &models.CustomResponse{
+			Status: http.StatusInternalServerError,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Summary report built successfully",
+		Data:    summary,
+	})
+}
+
+func buildSummaryQuery(userID int, queryForm form.SummaryQueryInput, now time.Time) (repository.SummaryQuery, error) {
+	query := repository.SummaryQuery{
+		UserID:   userID,
+		GroupBy:  repository.ReportPeriod(queryForm.GroupBy),
+		Location: time.UTC,
+		Currency: money.Currency(strings.ToUpper(queryForm.Currency)),
+	}
+	if query.GroupBy == "" {
+		query.GroupBy = repository.PeriodMonth
+	}
+
+	if queryForm.Timezone != "" {
+		location, err := loadLocation(queryForm.Timezone)
+		if err != nil {
+			return query, err
+		}
+		query.Location = location
+	}
+
+	localNow := now.In(query.Location)
+	query.From = time.Date(localNow.Year(), localNow.Month(), 1, 0, 0, 0, 0, query.Location)
+	query.To = query.From.AddDate(0, 1, 0)
+
+	if queryForm.From != "" {
+		from, err := time.ParseInLocation(dateLayout, queryForm.From, query.Location)
+		if err != nil {
+			return query, err
+		}
+		query.From = from
+	}
+	if queryForm.To != "" {
+		to, err := time.ParseInLocation(dateLayout, queryForm.To, query.Location)
+		if err != nil {
+			return query, err
+		}
+		// "to" is an inclusive day, the repository expects an exclusive bound.
+		query.To = to.AddDate(0, 0, 1)
+	}
+
+	if !query.From.Before(query.To) {
+		return query, errInvalidDateRange
+	}
+	return query, nil
+}
+
+// loadLocation resolves an IANA time zone name. time.LoadLocation also
+// accepts "Local" and the empty name, for the server's zone and UTC; both
+// are refused, since the name is passed on to Postgres, which knows neither.
+func loadLocation(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, errInvalidTimezone
+	}
+	return time.LoadLocation(name)
+}
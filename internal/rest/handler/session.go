@@ -0,0 +1,290 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go-finance-tracker/internal/models"
+	"go-finance-tracker/internal/repository"
+	"go-finance-tracker/internal/rest/form"
+	"go-finance-tracker/pkg/logger"
+	"go-finance-tracker/pkg/utils"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	accessTokenCookie  = "jwt"
+	refreshTokenCookie = "refresh_token"
+	// refreshTokenPath limits the refresh cookie to the auth endpoints.
+	refreshTokenPath = "/v1/auth"
+)
+
+// authTokens is returned in the response body to clients that send the
+// access token in the Authorization header.
+type authTokens struct {
+	AccessToken  string    `json:"accessToken"`
+	RefreshToken string    `json:"refreshToken,omitempty"`
+	TokenType    string    `json:"tokenType"`
+	ExpiresAt    time.Time `json:"expiresAt"`
+}
+
+// Refresh exchanges a refresh token for a new access token and a new refresh
+// token. Reusing an old refresh token revokes the session. A token sent in
+// the request body is answered in the body; otherwise the cookie is used.
+func (h *AuthHandlers) Refresh(ctx *gin.Context) {
+	var refreshForm form.RefreshInput
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&refreshForm); err != nil {
+			ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+				Status: http.StatusBadRequest,
+				Error:  err.Error(),
+			})
+			return
+		}
+	}
+
+	refreshToken := refreshForm.RefreshToken
+	inBody := refreshToken != ""
+	if !inBody {
+		refreshToken, _ = ctx.Cookie(refreshTokenCookie)
+	}
+	if refreshToken == "" {
+		ctx.JSON(http.StatusUnauthorized, &models.CustomResponse{
+			Status: http.StatusUnauthorized,
+			Error:  "Refresh token not found",
+		})
+		return
+	}
+
+	newRefreshToken, newRefreshHash, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		logger.GetLogger().Error("Failed to generate refresh token:", err)
+		ctx.JSON(http.StatusInternalServerError, &models.CustomResponse{
+			Status: http.StatusInternalServerError,
+			Error:  "Failed to generate refresh token",
+		})
+		return
+	}
+
+	session, err := h.SessionRepo.Rotate(
+		utils.HashOpaqueToken(refreshToken),
+		newRefreshHash,
+		ctx.ClientIP(),
+		userAgent(ctx),
+		time.Now().Add(h.Config.RefreshTokenTTL),
+	)
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidRefreshToken) || errors.Is(err, repository.ErrRefreshTokenReused) {
+			logger.GetLogger().Error("Refresh rejected:", err)
+			h.clearAuthCookies(ctx)
+			ctx.JSON(http.StatusUnauthorized, &models.CustomResponse{
+				Status: http.StatusUnauthorized,
+				Error:  err.Error(),
+			})
+			return
+		}
+		logger.GetLogger().Error("Failed to refresh session:", err)
+		ctx.JSON(http.StatusInternalServerError, &models.CustomResponse{
+			Status: http.StatusInternalServerError,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	user, err := h.UserRepo.GetUserByID(session.UserID)
+	if err != nil {
+		logger.GetLogger().Error("User does not exist:", err)
+		ctx.JSON(http.StatusUnauthorized, &models.CustomResponse{
+			Status: http.StatusUnauthorized,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	tokens, err := h.issueTokens(user, session, newRefreshToken)
+	if err != nil {
+		logger.GetLogger().Error("Failed to generate jwt token:", err)
+		ctx.JSON(http.StatusInternalServerError, &models.CustomResponse{
+			Status: http.StatusInternalServerError,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	response := &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Session refreshed successfully",
+	}
+	if inBody {
+		response.Data = tokens
+	} else {
+		h.setAuthCookies(ctx, tokens, session)
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+func (h *AuthHandlers) GetSessions(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	sessions, err := h.SessionRepo.GetActive(userID)
+	if err != nil {
+		logger.GetLogger().Error("Failed to fetch sessions:", err)
+		ctx.JSON(http.StatusInternalServerError, &models.CustomResponse{
+			Status: http.StatusInternalServerError,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	currentID := ctx.GetUint("sessionID")
+	for i := range sessions {
+		sessions[i].Current = sessions[i].ID == currentID
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Sessions fetched successfully",
+		Data:    sessions,
+	})
+}
+
+// DeleteSession revokes one of the user's sessions. The device is logged out
+// at once: ValidateToken refuses access tokens of revoked sessions.
+func (h *AuthHandlers) DeleteSession(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+	sessionID, ok := getIDParam(ctx)
+	if !ok {
+		return
+	}
+
+	if err := h.SessionRepo.Revoke(userID, sessionID); err != nil {
+		if errors.Is(err, repository.ErrSessionNotFound) {
+			ctx.JSON(http.StatusNotFound, &models.CustomResponse{
+				Status: http.StatusNotFound,
+				Error:  err.Error(),
+			})
+			return
+		}
+		logger.GetLogger().Error("Failed to revoke session:", err)
+		ctx.JSON(http.StatusInternalServerError, &models.CustomResponse{
+			Status: http.StatusInternalServerError,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	if sessionID == ctx.GetUint("sessionID") {
+		h.clearAuthCookies(ctx)
+	}
+
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Session revoked successfully",
+	})
+}
+
+// startSession creates a new session for user, sets its token cookies and
+// returns the tokens.
+func (h *AuthHandlers) startSession(ctx *gin.Context, user *models.User, device string) (*authTokens, error) {
+	refreshToken, refreshHash, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	session := models.Session{
+		UserID:     user.ID,
+		Device:     device,
+		IP:         ctx.ClientIP(),
+		UserAgent:  userAgent(ctx),
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(h.Config.RefreshTokenTTL),
+	}
+	if err := h.SessionRepo.Create(&session, refreshHash); err != nil {
+		return nil, err
+	}
+
+	tokens, err := h.issueTokens(user, &session, refreshToken)
+	if err != nil {
+		return nil, err
+	}
+	h.setAuthCookies(ctx, tokens, &session)
+	return tokens, nil
+}
+
+// issueTokens signs a new access token for session carrying the user's
+// roles and permissions. user.Roles must be loaded.
+func (h *AuthHandlers) issueTokens(user *models.User, session *models.Session, refreshToken string) (*authTokens, error) {
+	roles := make([]string, len(user.Roles))
+	roleIDs := make([]uint, len(user.Roles))
+	for i, role := range user.Roles {
+		roles[i] = role.Name
+		roleIDs[i] = role.ID
+	}
+
+	permissions, err := h.RoleRepo.GetPermissionNames(roleIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	accessToken, err := utils.CreateToken(models.Claims{
+		Id:           strconv.Itoa(int(user.ID)),
+		Username:     user.Username,
+		SessionID:    session.ID,
+		TokenVersion: user.TokenVersion,
+		Roles:        roles,
+		Permissions:  permissions,
+	}, h.Config.AccessTokenTTL)
+	if err != nil {
+		return nil, err
+	}
+
+	return &authTokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresAt:    time.Now().Add(h.Config.AccessTokenTTL),
+	}, nil
+}
+
+func (h *AuthHandlers) setAuthCookies(ctx *gin.Context, tokens *authTokens, session *models.Session) {
+	http.SetCookie(ctx.Writer, h.cookie(accessTokenCookie, tokens.AccessToken, "/", tokens.ExpiresAt))
+	http.SetCookie(ctx.Writer, h.cookie(refreshTokenCookie, tokens.RefreshToken, refreshTokenPath, session.ExpiresAt))
+}
+
+func (h *AuthHandlers) clearAuthCookies(ctx *gin.Context) {
+	expired := time.Now().Add(-time.Hour)
+	http.SetCookie(ctx.Writer, h.cookie(accessTokenCookie, "", "/", expired))
+	http.SetCookie(ctx.Writer, h.cookie(refreshTokenCookie, "", refreshTokenPath, expired))
+}
+
+// cookie builds a token cookie with the configured attributes.
+func (h *AuthHandlers) cookie(name, value, path string, expires time.Time) *http.Cookie {
+	return &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     path,
+		Domain:   h.Config.CookieDomain,
+		Expires:  expires,
+		Secure:   h.Config.CookieSecure,
+		HttpOnly: true,
+		SameSite: h.Config.CookieSameSite,
+	}
+}
+
+// userAgent returns the request's User-Agent cut to fit models.Session.
+func userAgent(ctx *gin.Context) string {
+	ua := ctx.Request.UserAgent()
+	if len(ua) > 255 {
+		ua = strings.ToValidUTF8(ua[:255], "")
+	}
+	return ua
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go-finance-tracker/internal/models"
+	"go-finance-tracker/internal/repository"
+	"go-finance-tracker/internal/rest/form"
+	"go-finance-tracker/pkg/logger"
+	"net/http"
+)
+
+type TransferHandlers struct {
+	transferRepo repository.TransferRepo
+}
+
+func NewTransferHandlers(transferRepo repository.TransferRepo) *TransferHandlers {
+	return &TransferHandlers{transferRepo: transferRepo}
+}
+
+// CreateTransfer moves money between two of the user's accounts. It creates
+// two linked finance records, which are deleted together through
+// /v1/finance/:id.
+func (h *TransferHandlers) CreateTransfer(ctx *gin.Context) {
+	userID, ok := getUserID(ctx)
+	if !ok {
+		return
+	}
+
+	var transferForm form.TransferInput
+	if err := ctx.ShouldBindJSON(&transferForm); err != nil {
+		logger.GetLogger().Error("Invalid transfer request:", err)
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+	if err := validate(transferForm); err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	transfer, err := h.transferRepo.Create(repository.NewTransfer{
+		UserID:        userID,
+		FromAccountID: transferForm.FromAccountID,
+		ToAccountID:   transferForm.ToAccountID,
+		Amount:        transferForm.Amount,
+		Note:          transferForm.Note,
+	})
+	if err != nil {
+		respondTransferError(ctx, "Failed to create transfer:", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, &models.CustomResponse{
+		Status:  http.StatusCreated,
+		Message: "Transfer created successfully",
+		Data:    transfer,
+	})
+}
+
+func respondTransferError(ctx *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repository.ErrAccountNotFound),
+		errors.Is(err, repository.ErrAccountArchived),
+		errors.Is(err, repository.ErrSameAccount),
+		errors.Is(err, repository.ErrTransferCurrencyMismatch):
+		status = http.StatusBadRequest
+	default:
+		logger.GetLogger().Error(message, err)
+	}
+
+	ctx.JSON(status, &models.CustomResponse{
+		Status: status,
+		Error:  err.Error(),
+	})
+}
@@ -1,8 +1,20 @@
 package handler
 
-import "github.com/go-playground/validator/v10"
+import (
+	"github.com/go-playground/validator/v10"
+	"go-finance-tracker/pkg/email"
+)
 
 func validate(data any) error {
 	validate := validator.New()
+	if err := validate.RegisterValidation("mailbox", isMailbox); err != nil {
+		return err
+	}
 	return validate.Struct(data)
 }
+
+// isMailbox backs the "mailbox" tag: it accepts the addresses the mailer
+// accepts, so that every address taken from a request can be mailed.
+func isMailbox(fl validator.FieldLevel) bool {
+	return email.IsEmailValid(fl.Field().String())
+}
@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"go-finance-tracker/internal/models"
+	"go-finance-tracker/internal/repository"
+	"go-finance-tracker/internal/rest/form"
+	"go-finance-tracker/pkg/email"
+	"go-finance-tracker/pkg/logger"
+	"go-finance-tracker/pkg/utils"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	emailVerificationTTL      = 48 * time.Hour
+	emailVerificationTemplate = "templates/verify_email.html"
+)
+
+// VerifyEmail handles the link sent by sendEmailVerification.
+func (h *AuthHandlers) VerifyEmail(ctx *gin.Context) {
+	token := ctx.Query("token")
+	if token == "" {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  "Missing token",
+		})
+		return
+	}
+
+	userID, err := h.UserTokenRepo.VerifyEmail(utils.HashOpaqueToken(token))
+	if err != nil {
+		if errors.Is(err, repository.ErrInvalidUserToken) {
+			ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+				Status: http.StatusBadRequest,
+				Error:  err.Error(),
+			})
+			return
+		}
+		logger.GetLogger().Error("Failed to verify email:", err)
+		ctx.JSON(http.StatusInternalServerError, &models.CustomResponse{
+			Status: http.StatusInternalServerError,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	logger.GetLogger().Info("Email verified for user ID:", userID)
+	ctx.JSON(http.StatusOK, &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "Email verified successfully",
+	})
+}
+
+// ResendVerification sends a new verification link to an unverified
+// address, at most once per configured cooldown. It needs no session, since
+// unverified users cannot log in, and the response is the same whether or
+// not the address is registered, so it cannot be used to probe for accounts.
+func (h *AuthHandlers) ResendVerification(ctx *gin.Context) {
+	var resendForm form.ResendVerificationInput
+	if err := ctx.ShouldBindJSON(&resendForm); err != nil {
+		logger.GetLogger().Error("Invalid verification resend request:", err)
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+	if err := validate(resendForm); err != nil {
+		ctx.JSON(http.StatusBadRequest, &models.CustomResponse{
+			Status: http.StatusBadRequest,
+			Error:  err.Error(),
+		})
+		return
+	}
+
+	response := &models.CustomResponse{
+		Status:  http.StatusOK,
+		Message: "If the address is registered and unverified, a verification link has been sent",
+	}
+
+	user, err := h.UserRepo.GetUserByEmail(resendForm.Email)
+	if err != nil {
+		if !errors.Is(err, repository.ErrUserNotFound) {
+			logger.GetLogger().Error("Failed to look up user by email:", err)
+		}
+		ctx.JSON(http.StatusOK, response)
+		return
+	}
+	if user.IsEmailVerified() {
+		ctx.JSON(http.StatusOK, response)
+		return
+	}
+
+	lastSent, err := h.UserTokenRepo.LastIssuedAt(user.ID, models.EmailVerificationToken)
+	if err != nil {
+		logger.GetLogger().Error("Failed to look up verification tokens:", err)
+		ctx.JSON(http.StatusOK, response)
+		return
+	}
+	// Within the cooldown nothing is sent, but the answer stays the same.
+	if lastSent != nil && time.Since(*lastSent) < h.Config.VerificationResendCooldown {
+		ctx.JSON(http.StatusOK, response)
+		return
+	}
+
+	if err := h.sendEmailVerification(user); err != nil {
+		logger.GetLogger().Error("Failed to issue verification token:", err)
+	}
+	ctx.JSON(http.StatusOK, response)
+}
+
+// sendEmailVerification stores a new verification token for user and emails
+// the link in the background.
+func (h *AuthHandlers) sendEmailVerification(user *models.User) error {
+	token, tokenHash, err := utils.GenerateOpaqueToken()
+	if err != nil {
+		return err
+	}
+
+	if err := h.UserTokenRepo.Issue(&models.UserToken{
+		UserID:    user.ID,
+		Purpose:   models.EmailVerificationToken,
+		TokenHash: tokenHash,
+		ExpiresAt: time.Now().Add(emailVerificationTTL),
+	}); err != nil {
+		return err
+	}
+
+	input := email.SendEmailInput{
+		To:      user.Email,
+		Subject: "Confirm your email address",
+	}
+	if err := input.GenerateBodyFromHTML(emailVerificationTemplate, map[string]string{
+		"Link":      h.Config.APIURL + "/v1/auth/verify?token=" + url.QueryEscape(token),
+		"ExpiresIn": "48 hours",
+	}); err != nil {
+		return err
+	}
+
+	go func() {
+		if err := h.EmailSender.Send(input); err != nil {
+			logger.GetLogger().Error("Failed to send verification email:", err)
+		}
+	}()
+	return nil
+}
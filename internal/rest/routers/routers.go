@@ -2,36 +2,170 @@ package routers
 
 import (
 	"github.com/gin-gonic/gin"
+	"go-finance-tracker/internal/config"
+	"go-finance-tracker/internal/models"
 	"go-finance-tracker/internal/rest/handler"
 	"go-finance-tracker/pkg/middleware"
+	"go-finance-tracker/pkg/ratelimit"
 )
 
 type Routers struct {
-	authHandler    *handler.AuthHandlers
-	financeHandler *handler.FinanceHandlers
+	authHandler      *handler.AuthHandlers
+	financeHandler   *handler.FinanceHandlers
+	accountHandler   *handler.AccountHandlers
+	transferHandler  *handler.TransferHandlers
+	ledgerHandler    *handler.LedgerHandlers
+	categoryHandler  *handler.CategoryHandlers
+	reportHandler    *handler.ReportHandlers
+	budgetHandler    *handler.BudgetHandlers
+	recurringHandler *handler.RecurringHandlers
+	rateHandler      *handler.ExchangeRateHandlers
+	adminHandler     *handler.AdminHandlers
 }
 
-func NewRouters(authHandler *handler.AuthHandlers, financeHandler *handler.FinanceHandlers) *Routers {
+func NewRouters(
+	authHandler *handler.AuthHandlers,
+	financeHandler *handler.FinanceHandlers,
+	accountHandler *handler.AccountHandlers,
+	transferHandler *handler.TransferHandlers,
+	ledgerHandler *handler.LedgerHandlers,
+	categoryHandler *handler.CategoryHandlers,
+	reportHandler *handler.ReportHandlers,
+	budgetHandler *handler.BudgetHandlers,
+	recurringHandler *handler.RecurringHandlers,
+	rateHandler *handler.ExchangeRateHandlers,
+	adminHandler *handler.AdminHandlers,
+) *Routers {
 	return &Routers{
-		authHandler:    authHandler,
-		financeHandler: financeHandler,
+		authHandler:      authHandler,
+		financeHandler:   financeHandler,
+		accountHandler:   accountHandler,
+		transferHandler:  transferHandler,
+		ledgerHandler:    ledgerHandler,
+		categoryHandler:  categoryHandler,
+		reportHandler:    reportHandler,
+		budgetHandler:    budgetHandler,
+		recurringHandler: recurringHandler,
+		rateHandler:      rateHandler,
+		adminHandler:     adminHandler,
 	}
 }
 
-func (r *Routers) SetupRoutes(app *gin.Engine) {
+// SetupRoutes registers the API routes, each group rate limited by limiter
+// according to limits.
+func (r *Routers) SetupRoutes(app *gin.Engine, limiter *ratelimit.Limiter, limits config.RateLimit) {
+	apiLimit := limiter.Middleware("api", limits.API, ratelimit.ByUserOrIP)
+
 	v1Router := app.Group("/v1")
 	{
-		authRouter := v1Router.Group("/auth")
+		authRouter := v1Router.Group("/auth", limiter.Middleware("auth", limits.Auth, ratelimit.ByIP))
 		{
 			authRouter.POST("/register", r.authHandler.Register)
 			authRouter.POST("/login", r.authHandler.Login)
+			authRouter.POST("/login/mfa", r.authHandler.LoginMFA)
 			authRouter.POST("/logout", middleware.RequireAuthMiddleware, r.authHandler.Logout)
 			authRouter.GET("/profile", middleware.RequireAuthMiddleware, r.authHandler.Profile)
+			authRouter.PATCH("/profile", middleware.RequireAuthMiddleware, r.authHandler.UpdateProfile)
+			authRouter.POST("/refresh", r.authHandler.Refresh)
+			authRouter.GET("/sessions", middleware.RequireAuthMiddleware, r.authHandler.GetSessions)
+			authRouter.DELETE("/sessions/:id", middleware.RequireAuthMiddleware, r.authHandler.DeleteSession)
+			authRouter.POST("/password/forgot", r.authHandler.ForgotPassword)
+			authRouter.POST("/password/reset", r.authHandler.ResetPassword)
+			authRouter.POST("/password/change", middleware.RequireAuthMiddleware, r.authHandler.ChangePassword)
+			authRouter.GET("/verify", r.authHandler.VerifyEmail)
+			authRouter.POST("/verify/resend", r.authHandler.ResendVerification)
+			authRouter.POST("/2fa/enroll", middleware.RequireAuthMiddleware, r.authHandler.EnrollMFA)
+			authRouter.POST("/2fa/confirm", middleware.RequireAuthMiddleware, r.authHandler.ConfirmMFA)
+			authRouter.POST("/2fa/disable", middleware.RequireAuthMiddleware, r.authHandler.DisableMFA)
+			authRouter.POST("/2fa/recovery-codes", middleware.RequireAuthMiddleware, r.authHandler.RegenerateRecoveryCodes)
 		}
-		financeRouter := v1Router.Group("/finance", middleware.RequireAuthMiddleware)
+		financeRouter := v1Router.Group("/finance", middleware.RequireAuthMiddleware, apiLimit)
 		{
 			financeRouter.GET("", r.financeHandler.GetAllFinance)
 			financeRouter.POST("", r.financeHandler.AddFinanceRecord)
+			financeRouter.GET("/:id", r.financeHandler.GetFinanceRecord)
+			financeRouter.PUT("/:id", r.financeHandler.UpdateFinanceRecord)
+			financeRouter.PATCH("/:id", r.financeHandler.PatchFinanceRecord)
+			financeRouter.DELETE("/:id", r.financeHandler.DeleteFinanceRecord)
+		}
+		accountRouter := v1Router.Group("/accounts", middleware.RequireAuthMiddleware, apiLimit)
+		{
+			accountRouter.GET("", r.accountHandler.GetAllAccounts)
+			accountRouter.POST("", r.accountHandler.CreateAccount)
+			accountRouter.GET("/:id", r.accountHandler.GetAccount)
+			accountRouter.PUT("/:id", r.accountHandler.UpdateAccount)
+			accountRouter.DELETE("/:id", r.accountHandler.DeleteAccount)
+		}
+		transferRouter := v1Router.Group("/transfers", middleware.RequireAuthMiddleware, apiLimit)
+		{
+			transferRouter.POST("", r.transferHandler.CreateTransfer)
+		}
+		ledgerRouter := v1Router.Group("/ledger", middleware.RequireAuthMiddleware, apiLimit)
+		{
+			ledgerRouter.GET("/accounts", r.ledgerHandler.GetLedgerAccounts)
+			ledgerRouter.POST("/accounts", r.ledgerHandler.CreateLedgerAccount)
+			ledgerRouter.GET("/entries", r.ledgerHandler.GetJournalEntries)
+			ledgerRouter.POST("/entries", r.ledgerHandler.CreateJournalEntry)
+			ledgerRouter.GET("/entries/:id", r.ledgerHandler.GetJournalEntry)
+			ledgerRouter.DELETE("/entries/:id", r.ledgerHandler.DeleteJournalEntry)
+			ledgerRouter.GET("/trial-balance", r.ledgerHandler.GetTrialBalance)
+			ledgerRouter.GET("/balance-sheet", r.ledgerHandler.GetBalanceSheet)
+		}
+		categoryRouter := v1Router.Group("/categories", middleware.RequireAuthMiddleware, apiLimit)
+		{
+			categoryRouter.GET("", r.categoryHandler.GetAllCategories)
+			categoryRouter.POST("", r.categoryHandler.CreateCategory)
+			categoryRouter.GET("/:id", r.categoryHandler.GetCategory)
+			categoryRouter.PUT("/:id", r.categoryHandler.UpdateCategory)
+			categoryRouter.DELETE("/:id", r.categoryHandler.DeleteCategory)
+			categoryRouter.POST("/:id/merge", r.categoryHandler.MergeCategory)
+		}
+		reportRouter := v1Router.Group("/reports", middleware.RequireAuthMiddleware,
+			limiter.Middleware("reports", limits.Reports, ratelimit.ByUserOrIP))
+		{
+			reportRouter.GET("/summary", r.reportHandler.GetSummary)
+		}
+		budgetRouter := v1Router.Group("/budgets", middleware.RequireAuthMiddleware, apiLimit)
+		{
+			budgetRouter.GET("", r.budgetHandler.GetAllBudgets)
+			budgetRouter.POST("", r.budgetHandler.CreateBudget)
+			budgetRouter.GET("/status", r.budgetHandler.GetAllBudgetStatuses)
+			budgetRouter.GET("/:id", r.budgetHandler.GetBudget)
+			budgetRouter.PUT("/:id", r.budgetHandler.UpdateBudget)
+			budgetRouter.DELETE("/:id", r.budgetHandler.DeleteBudget)
+			budgetRouter.GET("/:id/status", r.budgetHandler.GetBudgetStatus)
+		}
+		recurringRouter := v1Router.Group("/recurring", middleware.RequireAuthMiddleware, apiLimit)
+		{
+			recurringRouter.GET("", r.recurringHandler.GetAllRecurringRules)
+			recurringRouter.POST("", r.recurringHandler.CreateRecurringRule)
+			recurringRouter.GET("/:id", r.recurringHandler.GetRecurringRule)
+			recurringRouter.PUT("/:id", r.recurringHandler.UpdateRecurringRule)
+			recurringRouter.DELETE("/:id", r.recurringHandler.DeleteRecurringRule)
+			recurringRouter.GET("/:id/preview", r.recurringHandler.PreviewRecurringRule)
+		}
+		v1Router.GET("/exchange-rates", middleware.RequireAuthMiddleware, apiLimit, r.rateHandler.GetExchangeRates)
+		adminRouter := v1Router.Group("/admin", middleware.RequireAuthMiddleware,
+			limiter.Middleware("admin", limits.Admin, ratelimit.ByUserOrIP), middleware.RequireRole(models.RoleAdmin))
+		{
+			readUsers := middleware.RequirePermission(models.PermissionUsersRead)
+			writeUsers := middleware.RequirePermission(models.PermissionUsersWrite)
+			adminRouter.GET("/users", readUsers, r.adminHandler.GetUsers)
+			adminRouter.GET("/users/:id", readUsers, r.adminHandler.GetUser)
+			adminRouter.PATCH("/users/:id", writeUsers, r.adminHandler.UpdateUser)
+			adminRouter.DELETE("/users/:id", writeUsers, r.adminHandler.DeleteUser)
+			adminRouter.POST("/users/:id/restore", writeUsers, r.adminHandler.RestoreUser)
+			adminRouter.POST("/users/:id/roles", writeUsers, r.adminHandler.AssignRole)
+			adminRouter.DELETE("/users/:id/roles/:role", writeUsers, r.adminHandler.RevokeRole)
+			adminRouter.POST("/users/:id/lock", writeUsers, r.adminHandler.LockUser)
+			adminRouter.POST("/users/:id/unlock", writeUsers, r.adminHandler.UnlockUser)
+			adminRouter.POST("/users/:id/password-reset", writeUsers, r.adminHandler.ForcePasswordReset)
+			adminRouter.POST("/users/:id/2fa/reset", writeUsers, r.adminHandler.ResetMFA)
+			adminRouter.GET("/audit", middleware.RequirePermission(models.PermissionAuditRead), r.adminHandler.GetAuditLog)
+			adminRouter.POST("/balances/recompute",
+				middleware.RequirePermission(models.PermissionBalancesRecompute), r.adminHandler.RecomputeBalances)
+			adminRouter.POST("/exchange-rates",
+				middleware.RequirePermission(models.PermissionRatesWrite), r.rateHandler.ImportExchangeRates)
 		}
 	}
 }
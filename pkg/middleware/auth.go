@@ -2,23 +2,34 @@ package middleware
 
 import (
 	"github.com/gin-gonic/gin"
+	"go-finance-tracker/internal/models"
 	"go-finance-tracker/pkg/logger"
 	"go-finance-tracker/pkg/utils"
 	"net/http"
+	"strings"
 )
 
+// TokenValidator runs server-side checks on a token whose signature and
+// expiry have already been verified, e.g. rejecting tokens issued before a
+// password reset.
+type TokenValidator func(claims *models.Claims) error
+
+var tokenValidator TokenValidator
+
+func SetTokenValidator(validator TokenValidator) {
+	tokenValidator = validator
+}
+
 func RequireAuthMiddleware(c *gin.Context) {
 	log := logger.GetLogger()
 
-	var token string
-	cookie, err := c.Cookie("jwt")
-	if err != nil {
+	token, found := requestToken(c)
+	if !found {
 		log.Error("JWT token not found")
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "JWT token not found"})
 		c.AbortWithStatus(http.StatusUnauthorized)
 		return
 	}
-	token = cookie
 
 	if token == "" {
 		log.Error("Invalid token")
@@ -27,7 +38,7 @@ func RequireAuthMiddleware(c *gin.Context) {
 		return
 	}
 
-	id, username, err := utils.VerifyToken(token)
+	claims, err := utils.ParseToken(token)
 	if err != nil {
 		log.Error("Token verification failed:", err)
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token verification failed"})
@@ -35,9 +46,46 @@ func RequireAuthMiddleware(c *gin.Context) {
 		return
 	}
 
-	c.Set("id", id)
-	c.Set("username", username)
+	if claims.Purpose != "" {
+		log.Error("Token is not an access token:", claims.Purpose)
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token verification failed"})
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return
+	}
+
+	if tokenValidator != nil {
+		if err := tokenValidator(claims); err != nil {
+			log.Error("Token rejected:", err)
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Token is no longer valid"})
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		}
+	}
+
+	c.Set("id", claims.Id)
+	c.Set("username", claims.Username)
+	c.Set("sessionID", claims.SessionID)
+	c.Set("claims", claims)
 
 	log.Info("User is authenticated!")
 	c.Next()
 }
+
+// requestToken returns the access token from the "Authorization: Bearer"
+// header, falling back to the jwt cookie. A header with another scheme
+// yields an empty token rather than the cookie.
+func requestToken(c *gin.Context) (string, bool) {
+	if header := c.GetHeader("Authorization"); header != "" {
+		scheme, token, _ := strings.Cut(header, " ")
+		if !strings.EqualFold(scheme, "Bearer") {
+			return "", true
+		}
+		return strings.TrimSpace(token), true
+	}
+
+	cookie, err := c.Cookie("jwt")
+	if err != nil {
+		return "", false
+	}
+	return cookie, true
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+	"go-finance-tracker/internal/models"
+	"go-finance-tracker/pkg/logger"
+	"net/http"
+)
+
+// RequireRole allows the request when the token carries any of roles. It
+// must run after RequireAuthMiddleware.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := requestClaims(c)
+		if !ok {
+			return
+		}
+
+		for _, role := range roles {
+			if claims.HasRole(role) {
+				c.Next()
+				return
+			}
+		}
+
+		forbid(c, claims)
+	}
+}
+
+// RequirePermission allows the request when the token carries every one of
+// permissions. It must run after RequireAuthMiddleware.
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := requestClaims(c)
+		if !ok {
+			return
+		}
+
+		for _, permission := range permissions {
+			if !claims.HasPermission(permission) {
+				forbid(c, claims)
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+func requestClaims(c *gin.Context) (*models.Claims, bool) {
+	value, exists := c.Get("claims")
+	claims, ok := value.(*models.Claims)
+	if !exists || !ok {
+		logger.GetLogger().Error("Authorization checked before authentication")
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		c.AbortWithStatus(http.StatusUnauthorized)
+		return nil, false
+	}
+	return claims, true
+}
+
+func forbid(c *gin.Context, claims *models.Claims) {
+	logger.GetLogger().Errorf("Access to %s denied for user %s", c.FullPath(), claims.Username)
+	c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+	c.AbortWithStatus(http.StatusForbidden)
+}
@@ -0,0 +1,224 @@
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Scale is the number of fractional digits kept for every amount.
+const Scale = 2
+
+const minorPerUnit = 100
+
+var (
+	ErrInvalidAmount   = errors.New("invalid amount")
+	ErrAmountPrecision = errors.New("amount has more than 2 fractional digits")
+	ErrAmountOverflow  = errors.New("amount is out of range")
+)
+
+// Amount is an exact monetary value stored as an integer number of minor
+// units (cents). It is serialized to JSON as a decimal string and stored in
+// Postgres as numeric(19,2), so no value ever goes through a float.
+type Amount int64
+
+// FromMinor builds an amount from a number of minor units.
+func FromMinor(minor int64) Amount {
+	return Amount(minor)
+}
+
+// Parse reads a decimal string such as "12", "-0.5" or "1234.56".
+func Parse(s string) (Amount, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, ErrInvalidAmount
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	whole, frac, hasPoint := strings.Cut(s, ".")
+	if whole == "" && frac == "" || hasPoint && frac == "" {
+		return 0, ErrInvalidAmount
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	if !isDigits(whole) || !isDigits(frac) {
+		return 0, ErrInvalidAmount
+	}
+
+	// Trailing zeros beyond the scale carry no value ("1.500" == "1.50").
+	frac = strings.TrimRight(frac, "0")
+	if len(frac) > Scale {
+		return 0, ErrAmountPrecision
+	}
+	frac += strings.Repeat("0", Scale-len(frac))
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > math.MaxInt64/minorPerUnit {
+		return 0, ErrAmountOverflow
+	}
+	cents, _ := strconv.ParseInt(frac, 10, 64)
+
+	minor := units*minorPerUnit + cents
+	if minor < 0 {
+		return 0, ErrAmountOverflow
+	}
+	if negative {
+		minor = -minor
+	}
+	return Amount(minor), nil
+}
+
+// MustParse is like Parse but panics on error. Intended for constants.
+func MustParse(s string) Amount {
+	a, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+func (a Amount) Minor() int64 {
+	return int64(a)
+}
+
+func (a Amount) Add(b Amount) Amount {
+	return a + b
+}
+
+func (a Amount) Sub(b Amount) Amount {
+	return a - b
+}
+
+func (a Amount) Neg() Amount {
+	return -a
+}
+
+func (a Amount) IsZero() bool {
+	return a == 0
+}
+
+func (a Amount) IsNegative() bool {
+	return a < 0
+}
+
+func (a Amount) IsPositive() bool {
+	return a > 0
+}
+
+// String formats the amount with exactly Scale fractional digits.
+func (a Amount) String() string {
+	minor := int64(a)
+	sign := ""
+	if minor < 0 {
+		sign = "-"
+	}
+
+	// Work on the unsigned magnitude so that math.MinInt64 formats correctly.
+	magnitude := uint64(minor)
+	if minor < 0 {
+		magnitude = uint64(-(minor + 1)) + 1
+	}
+	return fmt.Sprintf("%s%d.%0*d", sign, magnitude/minorPerUnit, Scale, magnitude%minorPerUnit)
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(a.String())), nil
+}
+
+// UnmarshalJSON accepts both decimal strings ("12.50") and JSON numbers
+// (12.5). Numbers are parsed from their literal text, never through a float.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	text := string(data)
+	if text == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	} else if !json.Valid(data) {
+		return ErrInvalidAmount
+	}
+
+	parsed, err := Parse(text)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+func (a Amount) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+func (a *Amount) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// Scan implements sql.Scanner for numeric columns.
+func (a *Amount) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*a = 0
+		return nil
+	case string:
+		return a.scanNumeric(v)
+	case []byte:
+		return a.scanNumeric(string(v))
+	case int64:
+		if v > math.MaxInt64/minorPerUnit || v < math.MinInt64/minorPerUnit {
+			return ErrAmountOverflow
+		}
+		*a = Amount(v * minorPerUnit)
+		return nil
+	case float64:
+		// Only reached for legacy float columns; round to the nearest minor unit.
+		*a = Amount(math.Round(v * minorPerUnit))
+		return nil
+	}
+	return fmt.Errorf("money: cannot scan %T into Amount", src)
+}
+
+func (a *Amount) scanNumeric(s string) error {
+	parsed, err := Parse(s)
+	if err != nil {
+		return fmt.Errorf("money: cannot scan %q: %w", s, err)
+	}
+	*a = parsed
+	return nil
+}
+
+// Value implements driver.Valuer; the decimal string is cast to numeric by Postgres.
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+func (Amount) GormDataType() string {
+	return "numeric(19,2)"
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
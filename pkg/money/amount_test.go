@@ -0,0 +1,190 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Amount
+		err  error
+	}{
+		{in: "12", want: 1200},
+		{in: "-0.5", want: -50},
+		{in: "+3", want: 300},
+		{in: "1234.56", want: 123456},
+		{in: ".5", want: 50},
+		{in: " 7.01 ", want: 701},
+		{in: "1.500", want: 150},
+		{in: "0", want: 0},
+		{in: "-0", want: 0},
+		{in: "92233720368547758.07", want: math.MaxInt64},
+		{in: "", err: ErrInvalidAmount},
+		{in: "-", err: ErrInvalidAmount},
+		{in: ".", err: ErrInvalidAmount},
+		{in: "1.", err: ErrInvalidAmount},
+		{in: "1e3", err: ErrInvalidAmount},
+		{in: "1,5", err: ErrInvalidAmount},
+		{in: "--1", err: ErrInvalidAmount},
+		{in: "1.234", err: ErrAmountPrecision},
+		{in: "0.001", err: ErrAmountPrecision},
+		{in: "92233720368547758.08", err: ErrAmountOverflow},
+		{in: "100000000000000000000", err: ErrAmountOverflow},
+	}
+
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("Parse(%q) error = %v, want %v", tt.in, err, tt.err)
+			continue
+		}
+		if err == nil && got != tt.want {
+			t.Errorf("Parse(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestAmountString(t *testing.T) {
+	tests := []struct {
+		in   Amount
+		want string
+	}{
+		{in: 0, want: "0.00"},
+		{in: 5, want: "0.05"},
+		{in: -5, want: "-0.05"},
+		{in: 1250, want: "12.50"},
+		{in: -123456, want: "-1234.56"},
+		{in: math.MaxInt64, want: "92233720368547758.07"},
+		{in: math.MinInt64, want: "-92233720368547758.08"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.in.String(); got != tt.want {
+			t.Errorf("Amount(%d).String() = %q, want %q", int64(tt.in), got, tt.want)
+		}
+	}
+}
+
+func TestAmountArithmetic(t *testing.T) {
+	a, b := MustParse("10.25"), MustParse("0.75")
+
+	if got := a.Add(b); got != 1100 {
+		t.Errorf("Add = %s, want 11.00", got)
+	}
+	if got := b.Sub(a); got != -950 {
+		t.Errorf("Sub = %s, want -9.50", got)
+	}
+	if got := a.Neg(); got != -1025 || !got.IsNegative() || got.IsPositive() {
+		t.Errorf("Neg = %s, want -10.25", got)
+	}
+	if !a.Sub(a).IsZero() {
+		t.Errorf("a.Sub(a) is not zero")
+	}
+	if got := FromMinor(42).Minor(); got != 42 {
+		t.Errorf("FromMinor(42).Minor() = %d", got)
+	}
+}
+
+func TestAmountJSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Amount Amount `json:"amount"`
+	}{Amount: 1250})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), `{"amount":"12.50"}`; got != want {
+		t.Errorf("Marshal = %s, want %s", got, want)
+	}
+
+	tests := []struct {
+		in   string
+		want Amount
+		err  error
+	}{
+		{in: `"12.50"`, want: 1250},
+		{in: `12.5`, want: 1250},
+		{in: `-3`, want: -300},
+		// Large numbers must not lose precision through a float.
+		{in: `90071992547409.93`, want: 9007199254740993},
+		{in: `null`, want: 99},
+		{in: `"1.234"`, err: ErrAmountPrecision},
+		{in: `1.234`, err: ErrAmountPrecision},
+		{in: `"abc"`, err: ErrInvalidAmount},
+		{in: `true`, err: ErrInvalidAmount},
+		{in: `{`, err: ErrInvalidAmount},
+	}
+
+	for _, tt := range tests {
+		got := Amount(99)
+		err := got.UnmarshalJSON([]byte(tt.in))
+		if !errors.Is(err, tt.err) {
+			t.Errorf("UnmarshalJSON(%s) error = %v, want %v", tt.in, err, tt.err)
+			continue
+		}
+		if err == nil && got != tt.want {
+			t.Errorf("UnmarshalJSON(%s) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestAmountText(t *testing.T) {
+	text, err := Amount(-705).MarshalText()
+	if err != nil || string(text) != "-7.05" {
+		t.Errorf("MarshalText = %q, %v, want \"-7.05\"", text, err)
+	}
+
+	var a Amount
+	if err := a.UnmarshalText([]byte("8.1")); err != nil || a != 810 {
+		t.Errorf("UnmarshalText(8.1) = %s, %v, want 8.10", a, err)
+	}
+	if err := a.UnmarshalText([]byte("x")); !errors.Is(err, ErrInvalidAmount) {
+		t.Errorf("UnmarshalText(x) error = %v, want %v", err, ErrInvalidAmount)
+	}
+}
+
+func TestAmountScan(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     any
+		want    Amount
+		wantErr bool
+	}{
+		{name: "nil", src: nil, want: 0},
+		{name: "numeric text", src: "12.30", want: 1230},
+		{name: "numeric bytes", src: []byte("-0.07"), want: -7},
+		{name: "integer", src: int64(5), want: 500},
+		{name: "float rounds to the nearest cent", src: 0.1 + 0.2, want: 30},
+		{name: "float rounds half away from zero", src: -2.675000001, want: -268},
+		{name: "too precise", src: "1.001", wantErr: true},
+		{name: "not a number", src: "NaN", wantErr: true},
+		{name: "integer overflow", src: int64(math.MaxInt64), wantErr: true},
+		{name: "unsupported type", src: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Amount(99)
+			err := got.Scan(tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Scan(%v) error = %v, wantErr %v", tt.src, err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("Scan(%v) = %s, want %s", tt.src, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAmountValue(t *testing.T) {
+	value, err := Amount(-1230).Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != "-12.30" {
+		t.Errorf("Value = %v, want -12.30", value)
+	}
+}
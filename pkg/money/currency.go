@@ -0,0 +1,46 @@
+package money
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrInvalidCurrency = errors.New("invalid currency code")
+
+// Currency is an ISO 4217 alphabetic currency code such as "USD".
+type Currency string
+
+// DefaultCurrency is used for amounts that carry no explicit currency.
+const DefaultCurrency Currency = "USD"
+
+// ParseCurrency normalizes and validates a three-letter currency code.
+func ParseCurrency(s string) (Currency, error) {
+	code := strings.ToUpper(strings.TrimSpace(s))
+	if len(code) != 3 {
+		return "", ErrInvalidCurrency
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return "", ErrInvalidCurrency
+		}
+	}
+	return Currency(code), nil
+}
+
+func (c Currency) String() string {
+	return string(c)
+}
+
+// Money pairs an amount with the currency it is expressed in.
+type Money struct {
+	Amount   Amount   `json:"amount"`
+	Currency Currency `json:"currency"`
+}
+
+func New(amount Amount, currency Currency) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+func (m Money) String() string {
+	return m.Amount.String() + " " + string(m.Currency)
+}
@@ -0,0 +1,58 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestParseCurrency(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Currency
+		err  error
+	}{
+		{in: "USD", want: "USD"},
+		{in: "eur", want: "EUR"},
+		{in: " kzt ", want: "KZT"},
+		{in: "", err: ErrInvalidCurrency},
+		{in: "US", err: ErrInvalidCurrency},
+		{in: "USDT", err: ErrInvalidCurrency},
+		{in: "U1D", err: ErrInvalidCurrency},
+		{in: "ÜSD", err: ErrInvalidCurrency},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseCurrency(tt.in)
+		if !errors.Is(err, tt.err) {
+			t.Errorf("ParseCurrency(%q) error = %v, want %v", tt.in, err, tt.err)
+			continue
+		}
+		if err == nil && got != tt.want {
+			t.Errorf("ParseCurrency(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMoney(t *testing.T) {
+	m := New(MustParse("-4.5"), "EUR")
+	if got, want := m.String(), "-4.50 EUR"; got != want {
+		t.Errorf("String = %q, want %q", got, want)
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := string(data), `{"amount":"-4.50","currency":"EUR"}`; got != want {
+		t.Errorf("Marshal = %s, want %s", got, want)
+	}
+
+	var decoded Money
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded != m {
+		t.Errorf("Unmarshal = %+v, want %+v", decoded, m)
+	}
+}
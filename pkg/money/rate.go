@@ -0,0 +1,111 @@
+package money
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// RateScale is the number of fractional digits kept for exchange rates.
+const RateScale = 10
+
+var ErrInvalidRate = errors.New("invalid exchange rate")
+
+// Rate is an exchange rate: how many units of one currency a single unit of
+// another buys. Like Amount it never goes through a float; it is kept as its
+// canonical decimal text and stored in Postgres as numeric(24,10), where
+// conversions are computed.
+type Rate string
+
+// ParseRate reads a positive decimal string such as "0.92" or "478.15".
+func ParseRate(s string) (Rate, error) {
+	s = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(s), "+"))
+	whole, frac, hasPoint := strings.Cut(s, ".")
+	if whole == "" && frac == "" || hasPoint && frac == "" {
+		return "", ErrInvalidRate
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	if !isDigits(whole) || !isDigits(frac) || len(whole) > 14 {
+		return "", ErrInvalidRate
+	}
+
+	whole = strings.TrimLeft(whole, "0")
+	if whole == "" {
+		whole = "0"
+	}
+	frac = strings.TrimRight(frac, "0")
+	if len(frac) > RateScale {
+		return "", ErrInvalidRate
+	}
+	if whole == "0" && frac == "" {
+		return "", ErrInvalidRate
+	}
+
+	if frac == "" {
+		return Rate(whole), nil
+	}
+	return Rate(whole + "." + frac), nil
+}
+
+func (r Rate) String() string {
+	return string(r)
+}
+
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(r.String())), nil
+}
+
+// UnmarshalJSON accepts both decimal strings and JSON numbers, reading
+// numbers from their literal text.
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	text := string(data)
+	if text == "null" {
+		return nil
+	}
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	} else if !json.Valid(data) {
+		return ErrInvalidRate
+	}
+
+	parsed, err := ParseRate(text)
+	if err != nil {
+		return err
+	}
+	*r = parsed
+	return nil
+}
+
+// Scan implements sql.Scanner for numeric columns.
+func (r *Rate) Scan(src any) error {
+	var text string
+	switch v := src.(type) {
+	case string:
+		text = v
+	case []byte:
+		text = string(v)
+	default:
+		return fmt.Errorf("money: cannot scan %T into Rate", src)
+	}
+
+	parsed, err := ParseRate(text)
+	if err != nil {
+		return fmt.Errorf("money: cannot scan %q: %w", text, err)
+	}
+	*r = parsed
+	return nil
+}
+
+// Value implements driver.Valuer; the decimal string is cast to numeric by Postgres.
+func (r Rate) Value() (driver.Value, error) {
+	return r.String(), nil
+}
+
+func (Rate) GormDataType() string {
+	return "numeric(24,10)"
+}
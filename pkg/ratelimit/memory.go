@@ -0,0 +1,68 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps buckets in process memory, so every API instance
+// enforces its own limits.
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+
+	sweepInterval time.Duration
+	lastSweep     time.Time
+}
+
+type memoryBucket struct {
+	bucket
+	// full is when the bucket will have refilled completely; after that it
+	// is indistinguishable from a new one and can be evicted.
+	full time.Time
+}
+
+// NewMemoryStore returns a store that evicts refilled buckets at most once
+// per sweepInterval.
+func NewMemoryStore(sweepInterval time.Duration) *MemoryStore {
+	return &MemoryStore{
+		buckets:       make(map[string]*memoryBucket),
+		sweepInterval: sweepInterval,
+	}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) >= s.sweepInterval {
+		s.sweep(now)
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &memoryBucket{bucket: bucket{tokens: float64(limit.Requests), last: now}}
+		s.buckets[key] = b
+	}
+
+	result := b.take(limit, now)
+	b.full = now.Add(result.Reset)
+	return result, nil
+}
+
+// Len returns the number of buckets currently held.
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.buckets)
+}
+
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		if !now.Before(b.full) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"github.com/gin-gonic/gin"
+	"go-finance-tracker/pkg/logger"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// KeyFunc identifies the client a request is counted against.
+type KeyFunc func(c *gin.Context) string
+
+// ByIP counts requests per client IP.
+func ByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// ByUserOrIP counts requests per authenticated user, falling back to the
+// client IP. It must run after RequireAuthMiddleware to see the user.
+func ByUserOrIP(c *gin.Context) string {
+	if id := c.GetString("id"); id != "" {
+		return "user:" + id
+	}
+	return ByIP(c)
+}
+
+type Limiter struct {
+	store Store
+}
+
+func NewLimiter(store Store) *Limiter {
+	return &Limiter{store: store}
+}
+
+// Middleware limits the requests of each client, as identified by key, to
+// limit. Routes sharing a name share their buckets. Responses carry the
+// RateLimit-Limit, RateLimit-Remaining and RateLimit-Reset headers, and
+// Retry-After when the request is refused. If the store fails the request
+// is let through.
+func (l *Limiter) Middleware(name string, limit Limit, key KeyFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		result, err := l.store.Take(c.Request.Context(), name+":"+key(c), limit, time.Now())
+		if err != nil {
+			logger.GetLogger().Error("Rate limit store failed:", err)
+			c.Next()
+			return
+		}
+
+		c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+
+		if !result.Allowed {
+			c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Rate limit exceeded"})
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,103 @@
+// Package ratelimit limits requests per client with token buckets.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidLimit = errors.New(`limit must look like "100/1m"`)
+
+// Limit allows bursts of up to Requests, refilling the whole bucket evenly
+// over Per.
+type Limit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// ParseLimit parses "<requests>/<duration>", e.g. "100/1m".
+func ParseLimit(s string) (Limit, error) {
+	requests, per, found := strings.Cut(s, "/")
+	if !found {
+		return Limit{}, ErrInvalidLimit
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(requests))
+	if err != nil || n <= 0 {
+		return Limit{}, ErrInvalidLimit
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(per))
+	if err != nil || d <= 0 {
+		return Limit{}, ErrInvalidLimit
+	}
+	return Limit{Requests: n, Per: d}, nil
+}
+
+func (l Limit) String() string {
+	return fmt.Sprintf("%d/%s", l.Requests, l.Per)
+}
+
+// rate returns the refill rate in tokens per second.
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Per.Seconds()
+}
+
+// Result describes the bucket after a request was counted against it.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset is how long until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is how long until the next request is allowed; zero when
+	// this one was.
+	RetryAfter time.Duration
+}
+
+// Store keeps the token buckets. Implementations must be safe for
+// concurrent use; one shared by several API instances (e.g. Redis) makes
+// the limits global.
+type Store interface {
+	// Take removes a token from the bucket identified by key, creating a
+	// full bucket for limit when there is none.
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
+
+// bucket is a token bucket state shared by the store implementations.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// take refills b for the time elapsed since its last use and tries to
+// remove one token.
+func (b *bucket) take(limit Limit, now time.Time) Result {
+	capacity := float64(limit.Requests)
+	rate := limit.rate()
+
+	if elapsed := now.Sub(b.last).Seconds(); elapsed > 0 {
+		b.tokens += elapsed * rate
+		if b.tokens > capacity {
+			b.tokens = capacity
+		}
+	}
+	b.last = now
+
+	result := Result{Limit: limit.Requests}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = seconds((1 - b.tokens) / rate)
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = seconds((capacity - b.tokens) / rate)
+	return result
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
@@ -0,0 +1,160 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParseLimit(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Limit
+		wantErr bool
+	}{
+		{in: "100/1m", want: Limit{Requests: 100, Per: time.Minute}},
+		{in: " 5 / 30s ", want: Limit{Requests: 5, Per: 30 * time.Second}},
+		{in: "100", wantErr: true},
+		{in: "0/1m", wantErr: true},
+		{in: "-1/1m", wantErr: true},
+		{in: "x/1m", wantErr: true},
+		{in: "10/0s", wantErr: true},
+		{in: "10/minute", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseLimit(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseLimit(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if err == nil && got != tt.want {
+			t.Errorf("ParseLimit(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+// The tests below use a limit refilling one token per second, so that the
+// expected durations are exact.
+var testLimit = Limit{Requests: 3, Per: 3 * time.Second}
+
+func TestMemoryStoreTake(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+
+	type step struct {
+		at   time.Duration
+		key  string
+		want Result
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "burst up to the limit",
+			steps: []step{
+				{at: 0, want: Result{Allowed: true, Limit: 3, Remaining: 2, Reset: time.Second}},
+				{at: 0, want: Result{Allowed: true, Limit: 3, Remaining: 1, Reset: 2 * time.Second}},
+				{at: 0, want: Result{Allowed: true, Limit: 3, Remaining: 0, Reset: 3 * time.Second}},
+				{at: 0, want: Result{Limit: 3, Remaining: 0, Reset: 3 * time.Second, RetryAfter: time.Second}},
+			},
+		},
+		{
+			name: "refill over time",
+			steps: []step{
+				{at: 0, want: Result{Allowed: true, Limit: 3, Remaining: 2, Reset: time.Second}},
+				{at: 0, want: Result{Allowed: true, Limit: 3, Remaining: 1, Reset: 2 * time.Second}},
+				{at: 0, want: Result{Allowed: true, Limit: 3, Remaining: 0, Reset: 3 * time.Second}},
+				{at: 500 * time.Millisecond, want: Result{
+					Limit: 3, Remaining: 0, Reset: 2500 * time.Millisecond, RetryAfter: 500 * time.Millisecond,
+				}},
+				{at: time.Second, want: Result{Allowed: true, Limit: 3, Remaining: 0, Reset: 3 * time.Second}},
+				{at: 3 * time.Second, want: Result{Allowed: true, Limit: 3, Remaining: 1, Reset: 2 * time.Second}},
+			},
+		},
+		{
+			name: "refill stops at the limit",
+			steps: []step{
+				{at: 0, want: Result{Allowed: true, Limit: 3, Remaining: 2, Reset: time.Second}},
+				{at: time.Hour, want: Result{Allowed: true, Limit: 3, Remaining: 2, Reset: time.Second}},
+				{at: time.Hour, want: Result{Allowed: true, Limit: 3, Remaining: 1, Reset: 2 * time.Second}},
+				{at: time.Hour, want: Result{Allowed: true, Limit: 3, Remaining: 0, Reset: 3 * time.Second}},
+				{at: time.Hour, want: Result{Limit: 3, Remaining: 0, Reset: 3 * time.Second, RetryAfter: time.Second}},
+			},
+		},
+		{
+			name: "keys have their own buckets",
+			steps: []step{
+				{at: 0, key: "a", want: Result{Allowed: true, Limit: 3, Remaining: 2, Reset: time.Second}},
+				{at: 0, key: "a", want: Result{Allowed: true, Limit: 3, Remaining: 1, Reset: 2 * time.Second}},
+				{at: 0, key: "b", want: Result{Allowed: true, Limit: 3, Remaining: 2, Reset: time.Second}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewMemoryStore(time.Hour)
+			for i, s := range tt.steps {
+				key := s.key
+				if key == "" {
+					key = "client"
+				}
+				got, err := store.Take(context.Background(), key, testLimit, start.Add(s.at))
+				if err != nil {
+					t.Fatalf("step %d: %v", i, err)
+				}
+				if got != s.want {
+					t.Errorf("step %d: Take = %+v, want %+v", i, got, s.want)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	store := NewMemoryStore(time.Minute)
+	take := func(key string, limit Limit, at time.Duration) {
+		t.Helper()
+		if _, err := store.Take(context.Background(), key, limit, start.Add(at)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	slow := Limit{Requests: 1, Per: time.Hour}
+
+	// The first Take sweeps the empty store and starts the interval.
+	take("refilled", testLimit, 0)
+	take("draining", slow, 0)
+	take("late", testLimit, 30*time.Second)
+	if got := store.Len(); got != 3 {
+		t.Fatalf("Len before the sweep interval = %d, want 3", got)
+	}
+
+	// A minute later the buckets that refilled are evicted; the one that
+	// needs an hour is kept.
+	take("new", testLimit, time.Minute)
+	if got := store.Len(); got != 2 {
+		t.Fatalf("Len after the sweep = %d, want 2", got)
+	}
+	for _, key := range []string{"draining", "new"} {
+		if _, ok := store.buckets[key]; !ok {
+			t.Errorf("bucket %q was evicted", key)
+		}
+	}
+
+	// The evicted bucket starts again full, as it would have been.
+	got, err := store.Take(context.Background(), "refilled", testLimit, start.Add(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !got.Allowed || got.Remaining != 2 {
+		t.Errorf("Take after eviction = %+v, want a full bucket", got)
+	}
+
+	// No sweep happens until the interval has passed again.
+	take("other", testLimit, time.Minute+59*time.Second)
+	if got := store.Len(); got != 4 {
+		t.Errorf("Len within the sweep interval = %d, want 4", got)
+	}
+}
@@ -0,0 +1,161 @@
+package totp
+
+import (
+	"encoding/base32"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 key of the RFC 6238 test vectors.
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+// TestCodeRFC6238 checks the SHA-1 vectors of RFC 6238 appendix B. The RFC
+// lists 8-digit codes; 6-digit codes are their last six digits.
+func TestCodeRFC6238(t *testing.T) {
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{unix: 59, want: "94287082"},
+		{unix: 1111111109, want: "07081804"},
+		{unix: 1111111111, want: "14050471"},
+		{unix: 1234567890, want: "89005924"},
+		{unix: 2000000000, want: "69279037"},
+		{unix: 20000000000, want: "65353130"},
+	}
+
+	for _, tt := range tests {
+		got, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code at %d: %v", tt.unix, err)
+		}
+		if want := tt.want[len(tt.want)-Digits:]; got != want {
+			t.Errorf("Code at %d = %s, want %s", tt.unix, got, want)
+		}
+	}
+}
+
+func TestCodeInvalidSecret(t *testing.T) {
+	if _, err := Code("not base32!", 1); err != ErrInvalidSecret {
+		t.Errorf("Code error = %v, want %v", err, ErrInvalidSecret)
+	}
+}
+
+func TestCodeNormalizesSecret(t *testing.T) {
+	want, _ := Code(rfcSecret, 1)
+	got, err := Code(" "+strings.ToLower(rfcSecret)+" ", 1)
+	if err != nil || got != want {
+		t.Errorf("Code with a lower case secret = %s, %v, want %s", got, err, want)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	current := Step(now)
+	code := func(step int64) string {
+		c, err := Code(rfcSecret, step)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name     string
+		code     string
+		skew     int
+		wantStep int64
+		wantOK   bool
+	}{
+		{name: "current step", code: code(current), skew: 0, wantStep: current, wantOK: true},
+		{name: "spaces are ignored", code: code(current)[:3] + " " + code(current)[3:], skew: 0, wantStep: current, wantOK: true},
+		{name: "previous step within skew", code: code(current - 1), skew: 1, wantStep: current - 1, wantOK: true},
+		{name: "next step within skew", code: code(current + 1), skew: 1, wantStep: current + 1, wantOK: true},
+		{name: "previous step without skew", code: code(current - 1), skew: 0},
+		{name: "beyond skew", code: code(current - 2), skew: 1},
+		{name: "wrong code", code: "000000", skew: 1},
+		{name: "too short", code: code(current)[:5], skew: 1},
+		{name: "too long", code: code(current) + "0", skew: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			step, ok := Validate(rfcSecret, tt.code, now, tt.skew)
+			if ok != tt.wantOK || step != tt.wantStep {
+				t.Errorf("Validate = (%d, %v), want (%d, %v)", step, ok, tt.wantStep, tt.wantOK)
+			}
+		})
+	}
+}
+
+// TestValidateReplay checks that Validate reports the step a code belongs
+// to, which is what callers compare with the last accepted step to refuse
+// a code twice, also when it is replayed in the next step.
+func TestValidateReplay(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	code, _ := Code(rfcSecret, Step(now))
+
+	first, ok := Validate(rfcSecret, code, now, 1)
+	if !ok {
+		t.Fatal("code not accepted")
+	}
+	again, ok := Validate(rfcSecret, code, now.Add(Period), 1)
+	if !ok {
+		t.Fatal("code not accepted in the next step")
+	}
+	if again != first {
+		t.Errorf("replayed code matched step %d, want %d", again, first)
+	}
+
+	next, _ := Code(rfcSecret, Step(now)+1)
+	if step, ok := Validate(rfcSecret, next, now.Add(Period), 1); !ok || step <= first {
+		t.Errorf("next code matched step %d (%v), want one after %d", step, ok, first)
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := encoding.DecodeString(secret)
+	if err != nil {
+		t.Fatalf("secret %q is not base32: %v", secret, err)
+	}
+	if len(key) != secretSize {
+		t.Errorf("secret has %d bytes, want %d", len(key), secretSize)
+	}
+
+	other, _ := GenerateSecret()
+	if other == secret {
+		t.Error("two generated secrets are equal")
+	}
+}
+
+func TestURI(t *testing.T) {
+	uri, err := url.Parse(URI("Go Finance Tracker", "alice@example.com", rfcSecret))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if uri.Scheme != "otpauth" || uri.Host != "totp" {
+		t.Errorf("URI = %s, want otpauth://totp/...", uri)
+	}
+	if got, want := uri.Path, "/Go Finance Tracker:alice@example.com"; got != want {
+		t.Errorf("label = %q, want %q", got, want)
+	}
+
+	query := uri.Query()
+	for key, want := range map[string]string{
+		"secret":    rfcSecret,
+		"issuer":    "Go Finance Tracker",
+		"algorithm": "SHA1",
+		"digits":    "6",
+		"period":    "30",
+	} {
+		if got := query.Get(key); got != want {
+			t.Errorf("%s = %q, want %q", key, got, want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	MinPasswordLength = 8
+	// MaxPasswordBytes is the most bcrypt will hash.
+	MaxPasswordBytes = 72
+)
+
+var (
+	ErrPasswordTooShort = errors.New("password must be at least 8 characters long")
+	ErrPasswordTooLong  = errors.New("password must be at most 72 bytes long")
+	ErrPasswordTooWeak  = errors.New("password must contain both letters and digits")
+	ErrPasswordPersonal = errors.New("password must not contain the username or email")
+)
+
+// CheckPasswordPolicy reports whether password is acceptable for an account.
+// personal holds values the password must not contain, such as the username
+// and email; values shorter than 3 characters are ignored.
+func CheckPasswordPolicy(password string, personal ...string) error {
+	if utf8.RuneCountInString(password) < MinPasswordLength {
+		return ErrPasswordTooShort
+	}
+	if len(password) > MaxPasswordBytes {
+		return ErrPasswordTooLong
+	}
+
+	var hasLetter, hasDigit bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		}
+	}
+	if !hasLetter || !hasDigit {
+		return ErrPasswordTooWeak
+	}
+
+	lower := strings.ToLower(password)
+	for _, value := range personal {
+		// Only the local part of an email is likely to be reused.
+		value, _, _ = strings.Cut(strings.ToLower(value), "@")
+		if len(value) >= 3 && strings.Contains(lower, value) {
+			return ErrPasswordPersonal
+		}
+	}
+	return nil
+}
@@ -16,15 +16,13 @@ var (
 	ErrInvalidParsedToken    = errors.New("parsed token is invalid")
 )
 
-func CreateToken(id, username string) (tokenString string, err error) {
-	claims := &models.Claims{
-		Id:       id,
-		Username: username,
-		StandardClaims: jwt.StandardClaims{
-			ExpiresAt: time.Now().Add(time.Hour * 24).Unix(),
-		},
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+// CreateToken signs claims as an access token that expires after ttl.
+func CreateToken(claims models.Claims, ttl time.Duration) (tokenString string, err error) {
+	now := time.Now()
+	claims.IssuedAt = now.Unix()
+	claims.ExpiresAt = now.Add(ttl).Unix()
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &claims)
 	signedToken, err := token.SignedString([]byte(os.Getenv("JWT_SECRET")))
 	if err != nil {
 		return "", err
@@ -33,8 +31,17 @@ func CreateToken(id, username string) (tokenString string, err error) {
 }
 
 func VerifyToken(token string) (string, string, error) {
+	claims, err := ParseToken(token)
+	if err != nil {
+		return "", "", err
+	}
+	return claims.Id, claims.Username, nil
+}
+
+// ParseToken verifies the signature and expiry of token and returns its claims.
+func ParseToken(token string) (*models.Claims, error) {
 	if token == "" {
-		return "", "", ErrEmptyToken
+		return nil, ErrEmptyToken
 	}
 	claims := &models.Claims{}
 	parsedToken, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
@@ -42,15 +49,15 @@ func VerifyToken(token string) (string, string, error) {
 	})
 	if err != nil {
 		if errors.Is(err, jwt.ErrSignatureInvalid) {
-			return "", "", ErrInvalidTokenSignature
+			return nil, ErrInvalidTokenSignature
 		}
-		return "", "", ErrInvalidToken
+		return nil, ErrInvalidToken
 	}
 	if !parsedToken.Valid {
-		return "", "", ErrInvalidParsedToken
+		return nil, ErrInvalidParsedToken
 	}
 	if claims == nil {
-		return "", "", ErrEmptyTokenClaims
+		return nil, ErrEmptyTokenClaims
 	}
-	return claims.Id, claims.Username, nil
+	return claims, nil
 }